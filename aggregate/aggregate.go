@@ -0,0 +1,239 @@
+// Package aggregate provides windowed aggregations over the unconsumed elements of a gobuffer.Buffer. The
+// aggregations are maintained incrementally as elements are written to and consumed from the Buffer. That is,
+// querying an aggregate doesn't require reading the buffered elements.
+//
+// The aggregations are kept in a separate package to avoid forcing the type constraints on the Buffer itself.
+package aggregate
+
+import (
+	"cmp"
+
+	"github.com/habak67/gobuffer"
+)
+
+// Number is a constraint permitting the numeric types that support addition and subtraction.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// entry holds an element together with its absolute position in the Buffer.
+type entry[T any] struct {
+	pos   int
+	value T
+}
+
+// extreme tracks the extreme (min or max) value of a sliding window using a monotonic queue. The first entry in
+// the queue is the extreme value of the window.
+type extreme[T cmp.Ordered] struct {
+	less  func(a, b T) bool
+	queue []entry[T]
+}
+
+func (e *extreme[T]) push(pos int, value T) {
+	// Remove all values that can never become the extreme value as the new value will outlive them
+	for len(e.queue) > 0 && e.less(value, e.queue[len(e.queue)-1].value) {
+		e.queue = e.queue[:len(e.queue)-1]
+	}
+	e.queue = append(e.queue, entry[T]{pos: pos, value: value})
+}
+
+func (e *extreme[T]) pop(pos int) {
+	if len(e.queue) > 0 && e.queue[0].pos == pos {
+		e.queue = e.queue[1:]
+	}
+}
+
+func (e *extreme[T]) get() (value T, ok bool) {
+	if len(e.queue) == 0 {
+		return
+	}
+	return e.queue[0].value, true
+}
+
+func (e *extreme[T]) reset() {
+	e.queue = e.queue[:0]
+}
+
+// Ordered wraps a gobuffer.Buffer and maintains the min and max value of the unconsumed elements in the Buffer.
+// Writing and consuming elements update the aggregates in amortized constant time.
+//
+// The wrapped Buffer must only be modified through the Ordered wrapper. Otherwise, the aggregates will be
+// inconsistent with the Buffer content.
+type Ordered[T cmp.Ordered] struct {
+	buf     *gobuffer.Buffer[T]
+	written int // written holds the number of elements written to the Buffer.
+	min     extreme[T]
+	max     extreme[T]
+}
+
+// NewOrdered creates an Ordered wrapper for the provided Buffer. Any unconsumed elements already in the Buffer
+// are included in the aggregates.
+func NewOrdered[T cmp.Ordered](buf *gobuffer.Buffer[T]) *Ordered[T] {
+	o := &Ordered[T]{
+		buf: buf,
+		min: extreme[T]{less: func(a, b T) bool { return a < b }},
+		max: extreme[T]{less: func(a, b T) bool { return a > b }},
+	}
+	o.written = buf.Buffered()
+	o.recompute()
+	return o
+}
+
+// Buffer returns the wrapped Buffer. The returned Buffer may be used for read-only operations (like Buffer.Next).
+func (o *Ordered[T]) Buffer() *gobuffer.Buffer[T] {
+	return o.buf
+}
+
+// Write writes an element to the Buffer and adds the element to the aggregates. If the Buffer refuses the
+// element (like a frozen or full Buffer, see gobuffer.MisusePolicy) then the aggregates are left unchanged.
+func (o *Ordered[T]) Write(element T) {
+	o.write(element)
+}
+
+// write writes an element to the Buffer and adds the element to the aggregates. If the element was written then
+// true is returned.
+func (o *Ordered[T]) write(element T) bool {
+	total := o.buf.TotalWritten()
+	o.buf.Write(element)
+	if o.buf.TotalWritten() == total {
+		return false
+	}
+	o.min.push(o.written, element)
+	o.max.push(o.written, element)
+	o.written++
+	return true
+}
+
+// Next returns the next element from the Buffer (see gobuffer.Buffer.Next).
+func (o *Ordered[T]) Next() (T, bool) {
+	return o.buf.Next()
+}
+
+// Consume consumes the next element in the Buffer and removes the element from the aggregates.
+func (o *Ordered[T]) Consume() {
+	if o.buf.Buffered() == 0 {
+		return
+	}
+	pos := o.readPos()
+	o.buf.Consume()
+	o.min.pop(pos)
+	o.max.pop(pos)
+}
+
+// State returns a Buffer state (see gobuffer.Buffer.State).
+func (o *Ordered[T]) State() gobuffer.State {
+	return o.buf.State()
+}
+
+// Rollback rolls back the Buffer to the provided state (see gobuffer.Buffer.Rollback). As a rollback may
+// re-add any number of elements to the window the aggregates are recomputed from the unconsumed elements.
+func (o *Ordered[T]) Rollback(state gobuffer.State) error {
+	if err := o.buf.Rollback(state); err != nil {
+		return err
+	}
+	o.recompute()
+	return nil
+}
+
+// Commit commits the Buffer (see gobuffer.Buffer.Commit). A commit doesn't affect the aggregates.
+func (o *Ordered[T]) Commit() {
+	o.buf.Commit()
+}
+
+// Buffered returns the number of unconsumed elements in the Buffer.
+func (o *Ordered[T]) Buffered() int {
+	return o.buf.Buffered()
+}
+
+// Min returns the smallest unconsumed element in the Buffer. If the Buffer holds no unconsumed elements then
+// false is returned.
+func (o *Ordered[T]) Min() (T, bool) {
+	return o.min.get()
+}
+
+// Max returns the largest unconsumed element in the Buffer. If the Buffer holds no unconsumed elements then
+// false is returned.
+func (o *Ordered[T]) Max() (T, bool) {
+	return o.max.get()
+}
+
+// readPos returns the absolute position of the next element to read from the Buffer.
+func (o *Ordered[T]) readPos() int {
+	return o.written - o.buf.Buffered()
+}
+
+func (o *Ordered[T]) recompute() {
+	o.min.reset()
+	o.max.reset()
+	pos := o.readPos()
+	for chunk := range o.buf.Chunks() {
+		for _, e := range chunk {
+			o.min.push(pos, e)
+			o.max.push(pos, e)
+			pos++
+		}
+	}
+}
+
+// Numeric wraps a gobuffer.Buffer and maintains the min, max and sum of the unconsumed elements in the Buffer.
+// Writing and consuming elements update the aggregates in amortized constant time.
+//
+// Note that for floating point elements the sum is maintained by adding written elements and subtracting
+// consumed elements. Rounding errors may therefore accumulate over time.
+type Numeric[T Number] struct {
+	Ordered[T]
+	sum T
+}
+
+// NewNumeric creates a Numeric wrapper for the provided Buffer. Any unconsumed elements already in the Buffer
+// are included in the aggregates.
+func NewNumeric[T Number](buf *gobuffer.Buffer[T]) *Numeric[T] {
+	n := &Numeric[T]{Ordered: *NewOrdered(buf)}
+	n.recompute()
+	return n
+}
+
+// Write writes an element to the Buffer and adds the element to the aggregates. If the Buffer refuses the
+// element then the aggregates are left unchanged.
+func (n *Numeric[T]) Write(element T) {
+	if n.Ordered.write(element) {
+		n.sum += element
+	}
+}
+
+// Consume consumes the next element in the Buffer and removes the element from the aggregates.
+func (n *Numeric[T]) Consume() {
+	element, ok := n.buf.Next()
+	if !ok {
+		return
+	}
+	n.Ordered.Consume()
+	n.sum -= element
+}
+
+// Rollback rolls back the Buffer to the provided state (see gobuffer.Buffer.Rollback). As a rollback may
+// re-add any number of elements to the window the aggregates are recomputed from the unconsumed elements.
+func (n *Numeric[T]) Rollback(state gobuffer.State) error {
+	if err := n.Ordered.Rollback(state); err != nil {
+		return err
+	}
+	n.recompute()
+	return nil
+}
+
+// Sum returns the sum of the unconsumed elements in the Buffer. If the Buffer holds no unconsumed elements then
+// zero is returned.
+func (n *Numeric[T]) Sum() T {
+	return n.sum
+}
+
+func (n *Numeric[T]) recompute() {
+	n.sum = 0
+	for chunk := range n.buf.Chunks() {
+		for _, e := range chunk {
+			n.sum += e
+		}
+	}
+}
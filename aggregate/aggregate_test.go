@@ -0,0 +1,104 @@
+package aggregate
+
+import (
+	"testing"
+
+	"github.com/habak67/gobuffer"
+)
+
+func TestNumeric(t *testing.T) {
+	agg := NewNumeric(gobuffer.NewWithSize[int](3, 1))
+	for _, v := range []int{5, 1, 7, 3, 9, 2} {
+		agg.Write(v)
+	}
+	tests := []struct {
+		name    string
+		consume int
+		min     int
+		max     int
+		sum     int
+	}{
+		{"nothing consumed", 0, 1, 9, 27},
+		{"first consumed", 1, 1, 9, 22},
+		{"min consumed", 1, 2, 9, 21},
+		{"max before consumed", 2, 2, 9, 11},
+		{"max consumed", 1, 2, 2, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := 0; i < test.consume; i++ {
+				agg.Consume()
+			}
+			if v, _ := agg.Min(); v != test.min {
+				t.Errorf("unexpected min:\nexp=%d\ngot=%d", test.min, v)
+			}
+			if v, _ := agg.Max(); v != test.max {
+				t.Errorf("unexpected max:\nexp=%d\ngot=%d", test.max, v)
+			}
+			if v := agg.Sum(); v != test.sum {
+				t.Errorf("unexpected sum:\nexp=%d\ngot=%d", test.sum, v)
+			}
+		})
+	}
+	agg.Consume()
+	if _, ok := agg.Min(); ok {
+		t.Errorf("unexpected min for empty window")
+	}
+	if v := agg.Sum(); v != 0 {
+		t.Errorf("unexpected sum:\nexp=%d\ngot=%d", 0, v)
+	}
+}
+
+func TestNumeric_Rollback(t *testing.T) {
+	agg := NewNumeric(gobuffer.New[float64]())
+	for _, v := range []float64{1.5, -2, 4} {
+		agg.Write(v)
+	}
+	state := agg.State()
+	agg.Consume()
+	agg.Consume()
+	if err := agg.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	if v, _ := agg.Min(); v != -2 {
+		t.Errorf("unexpected min:\nexp=%v\ngot=%v", -2, v)
+	}
+	if v := agg.Sum(); v != 3.5 {
+		t.Errorf("unexpected sum:\nexp=%v\ngot=%v", 3.5, v)
+	}
+}
+
+func TestOrdered_Strings(t *testing.T) {
+	buf := gobuffer.New[string]()
+	buf.Write("m")
+	agg := NewOrdered(buf)
+	agg.Write("z")
+	agg.Write("a")
+	if v, _ := agg.Min(); v != "a" {
+		t.Errorf("unexpected min:\nexp=%s\ngot=%s", "a", v)
+	}
+	agg.Consume()
+	if v, _ := agg.Max(); v != "z" {
+		t.Errorf("unexpected max:\nexp=%s\ngot=%s", "z", v)
+	}
+}
+
+func TestNumeric_Refused(t *testing.T) {
+	agg := NewNumeric(gobuffer.New[int]().WithMaxElements(2))
+	for _, v := range []int{3, 1, -5} {
+		agg.Write(v)
+	}
+	agg.Buffer().Freeze()
+	agg.Write(-7)
+	if v, _ := agg.Min(); v != 1 {
+		t.Errorf("unexpected min:\nexp=%d\ngot=%d", 1, v)
+	}
+	if v := agg.Sum(); v != 4 {
+		t.Errorf("unexpected sum:\nexp=%d\ngot=%d", 4, v)
+	}
+	agg.Consume()
+	agg.Consume()
+	if _, ok := agg.Max(); ok {
+		t.Errorf("unexpected max for empty window")
+	}
+}
@@ -2,7 +2,7 @@ package gobuffer
 
 import (
 	"errors"
-	"fmt"
+	"strconv"
 )
 
 // position holds the position in a two-dimensional space (like a Buffer) consisting of rows and columns.
@@ -18,6 +18,11 @@ type position struct {
 // lowest position is 0/0, and you may not move before that position. That is, if the current position is 0/3 and
 // steps is -5 then the new position will be 0/0.
 func (p position) Move(steps int) position {
+	// Fast path when staying in the same row
+	if col := p.Col + steps; col >= 0 && col < p.rowSize {
+		p.Col = col
+		return p
+	}
 	absPos := p.AbsolutePos() + steps
 	if absPos < 0 {
 		absPos = 0
@@ -39,6 +44,49 @@ func (p position) AbsolutePos() int {
 
 var IllegalStateError = errors.New("rollback position doesn't exist")
 var ZeroStateError = errors.New("illegal non-initialized state")
+var IllegalWindowError = errors.New("window end before window start")
+var FrozenError = errors.New("write to frozen buffer")
+
+// argumentError is the panic value raised for an illegal argument. It is used instead of fmt.Errorf to keep the
+// core of the package free from fmt (which matters for small targets like TinyGo and WASM).
+type argumentError struct {
+	msg string
+	n   int
+}
+
+func (e argumentError) Error() string {
+	return e.msg + " " + strconv.Itoa(e.n)
+}
+
+// usageError is raised (as a panic) when a method is called in a way not covered by argumentError (e.g.
+// configuring a source option for a Buffer without source).
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// detailError adds a detail to an error returned by the package (like "position 7 not in [0, 5]"). It is used
+// instead of fmt.Errorf for the same reason as argumentError. Both the error and the cause (if any) are wrapped.
+type detailError struct {
+	err    error
+	detail string
+	cause  error
+}
+
+func (e detailError) Error() string {
+	if e.cause != nil {
+		return e.err.Error() + ": " + e.detail + ": " + e.cause.Error()
+	}
+	return e.err.Error() + ": " + e.detail
+}
+
+func (e detailError) Unwrap() []error {
+	if e.cause != nil {
+		return []error{e.err, e.cause}
+	}
+	return []error{e.err}
+}
 
 // State holds a state for a Buffer. It could be used to roll back to a previously saved state.
 type State struct {
@@ -55,6 +103,20 @@ func newState(read, write position) State {
 	}
 }
 
+// Diff describes what happened in a Buffer between the creation of the states a and b. The number of elements
+// consumed and written after state a was created and before state b was created is returned. If state b was
+// created before state a (or the Buffer was rolled back in between) then the number of consumed elements is
+// negative. If any of the states is the "zero state" then a ZeroStateError is returned.
+func Diff(a, b State) (consumed int, written int, err error) {
+	if !a.init || !b.init {
+		err = ZeroStateError
+		return
+	}
+	consumed = b.read.AbsolutePos() - a.read.AbsolutePos()
+	written = b.write.AbsolutePos() - a.write.AbsolutePos()
+	return
+}
+
 // Buffer is a dynamic FIFO Buffer holding elements of the specified type. The Buffer grows with increments of
 // the configured row size. The Buffer supports read, write, unread and rollback to a previously collected state.
 // A Buffer supports two models for lookahead.
@@ -81,13 +143,54 @@ type Buffer[T any] struct {
 	buffers  [][]T
 	read     position // read points to the next element to read from the Buffer.
 	write    position // write points to the position where the next element should be written.
+	// commitFinalizer is called for each element removed from the Buffer by Buffer.Commit.
+	commitFinalizer func(T)
+	// onConsumed is called for each element that may no longer be read (see Buffer.OnConsumed).
+	onConsumed func(T)
+	pinned     int // pinned holds the lowest read position of any state created (-1 if none).
+	released   int // released holds the position before which onConsumed has been called for all elements.
+	observer   Observer
+	frozen     bool // frozen is true if the Buffer is read-only (see Buffer.Freeze).
+	shared     int  // shared holds the row number before which rows may be shared with snapshots.
+	source     *source[T]
+	watchers   []*watcher[T]
+	stats      *Stats // stats holds the collected statistics (nil if not collected).
+	savepoints []*Savepoint[T]
+	// checkpoints holds the named savepoints (see Buffer.Checkpoint).
+	checkpoints map[string]*Savepoint[T]
+	cursors     []*Cursor[T]
+	// misusePolicy controls how misuse is handled and misuse holds the last ignored misuse (see MisusePolicy).
+	misusePolicy MisusePolicy
+	misuse       error
+	history      int // history holds the number of consumed elements retained by Commit (see Buffer.WithHistory).
+	maxElements  int // maxElements holds the maximum number of held elements (0 if unbounded).
+	// seqOffset holds the number of elements written but not counted by the write position (discarded from the
+	// back or written to the front) and seqMarks holds the shifts of the sequence numbers (see Buffer.NextSeq).
+	seqOffset int
+	seqMarks  []seqMark
+	// storage holds the rows (nil if allocated on the heap, see NewWithStorage).
+	storage RowStorage[T]
+}
+
+// embeddedRowSize is the maximum row size for which the first row is embedded in the Buffer (see NewWithSize).
+const embeddedRowSize = 16
+
+// smallBuffer is a Buffer allocated together with its first row (see NewWithSize). Only buffers with a row size
+// of at most embeddedRowSize are allocated as a smallBuffer. Hence, other buffers don't pay for the embedded row.
+type smallBuffer[T any] struct {
+	Buffer[T]
+	row  [embeddedRowSize]T
+	rows [1][]T
 }
 
 // Next returns the next element from the Buffer. If such next element exist then true is returned. If there
-// are no unread elements in the buffer then false is returned.
+// are no unread elements in the buffer then false is returned. If the Buffer has a source (see NewFromSource)
+// then more elements are pulled from the source before false is returned.
 func (b *Buffer[T]) Next() (element T, ok bool) {
-	if b.Buffered() == 0 {
-		return
+	for b.Buffered() == 0 {
+		if b.source == nil || !b.pull() {
+			return
+		}
 	}
 	ok = true
 	row, col := b.bufferPos(b.read)
@@ -95,25 +198,185 @@ func (b *Buffer[T]) Next() (element T, ok bool) {
 	return
 }
 
+// NextSeq returns the next element from the Buffer together with its sequence number. The sequence number is
+// the zero based index of the element among all elements ever written to the Buffer. That is, sequence numbers
+// are unique, not affected by Buffer.Commit, and may be used as stable identities of the buffered elements. An
+// element written after elements have been discarded (see Buffer.Truncate) or written to the front (see
+// Buffer.WriteFront) never reuses a sequence number. If there are no unread elements in the buffer then false is
+// returned.
+func (b *Buffer[T]) NextSeq() (element T, seq uint64, ok bool) {
+	if element, ok = b.Next(); ok {
+		seq = uint64(b.seq(b.read.AbsolutePos()))
+	}
+	return
+}
+
+// Pop returns the next element from the Buffer and consumes it. That is, Pop is equivalent to Buffer.Next
+// followed by Buffer.Consume (if an element was returned). If there are no unread elements in the Buffer then
+// false is returned (after pulling from the source of the Buffer, see NewFromSource).
+func (b *Buffer[T]) Pop() (element T, ok bool) {
+	if element, ok = b.Next(); ok {
+		b.consume(1)
+	}
+	return
+}
+
+// PeekN returns the next n unconsumed elements without consuming them. If the Buffer has fewer than n unconsumed
+// elements (after pulling from the source of the Buffer, see NewFromSource) then false is returned.
+//
+// If the elements are held in a single Buffer row then the returned slice references the row. Hence, the
+// returned slice must not be modified and is only valid until the Buffer is modified. If n is < 0 then a panic
+// is raised.
+func (b *Buffer[T]) PeekN(n int) ([]T, bool) {
+	if n < 0 {
+		panic(argumentError{"illegal negative peek length", n})
+	}
+	for b.Buffered() < n {
+		if b.source == nil || !b.pull() {
+			return nil, false
+		}
+	}
+	var scratch []T
+	return b.peek(n, &scratch), true
+}
+
 // Consume will consume the next element (returned by Buffer.Next) in the Buffer. The next element (returned by
 // Buffer.Next) will be the element after the previous next element.
+//
+// Consuming from an empty Buffer is a misuse (see MisusePolicy) and has no effect.
 func (b *Buffer[T]) Consume() {
 	// We only consume if there is an element to consume
 	if b.Buffered() > 0 {
-		b.read = b.read.Move(1)
+		b.consume(1)
+		return
+	}
+	_ = b.misused("Consume", EmptyConsumeError, false)
+}
+
+// ConsumeN consumes the next n elements in the Buffer in one operation. That is, the read position is moved n
+// elements forward.
+//
+// Consuming more elements than buffered is a misuse (see MisusePolicy) and has no effect. If n is < 0 then a
+// panic is raised.
+func (b *Buffer[T]) ConsumeN(n int) {
+	if n < 0 {
+		panic(argumentError{"illegal negative consume length", n})
+	}
+	if n <= b.Buffered() {
+		b.consume(n)
+		return
+	}
+	_ = b.misused("ConsumeN", EmptyConsumeError, false)
+}
+
+// Discard skips (consumes) up to n unconsumed elements and returns the number of skipped elements. Discard never
+// skips beyond the write position. That is, if the Buffer has fewer than n unconsumed elements then all of them
+// are skipped (see bufio.Reader.Discard). If n is < 0 then a panic is raised.
+//
+// Note that ByteBuffer.Discard hides Discard for a ByteBuffer.
+func (b *Buffer[T]) Discard(n int) int {
+	if n < 0 {
+		panic(argumentError{"illegal negative discard length", n})
+	}
+	n = min(n, b.Buffered())
+	b.consume(n)
+	return n
+}
+
+// consume moves the read position n elements forward. The caller must make sure that there are at least n
+// unconsumed elements in the Buffer.
+func (b *Buffer[T]) consume(n int) {
+	b.read = b.read.Move(n)
+	b.sample()
+	if b.onConsumed != nil {
+		pos := b.read.AbsolutePos()
+		if b.pinned >= 0 {
+			pos = min(pos, b.pinned)
+		}
+		b.release(pos)
+	}
+}
+
+// release calls onConsumed for all elements before the specified position not already released.
+func (b *Buffer[T]) release(pos int) {
+	from := max(b.released, b.startPos().AbsolutePos())
+	if pos <= from {
+		return
 	}
+	b.chunks(b.read.Move(from-b.read.AbsolutePos()), b.read.Move(pos-b.read.AbsolutePos()), func(chunk []T) bool {
+		for _, element := range chunk {
+			b.onConsumed(element)
+		}
+		return true
+	})
+	b.released = pos
+}
+
+// OnConsumed configures a hook called for each consumed element as soon as the element may no longer be read
+// again. That is, when no state may roll back the Buffer to a position before the element. This may happen much
+// earlier than the element being removed by Buffer.Commit (see Buffer.WithCommitFinalizer) and enables earlier
+// recycling of heavyweight elements. The hook is called exactly once for each consumed element. The Buffer is
+// returned to allow chaining.
+//
+// As states are plain values the Buffer can't know when a state is no longer used. Instead, the Buffer
+// conservatively assumes that any state created after the last commit may still be used. Consumed elements at or
+// after the read position of such a state are released by the next commit.
+//
+// The hook should be configured before any states are created.
+func (b *Buffer[T]) OnConsumed(fn func(T)) *Buffer[T] {
+	b.onConsumed = fn
+	return b
 }
 
 // Write writes an element to the Buffer. If needed the Buffer is grown to hold the element.
 func (b *Buffer[T]) Write(element T) {
-	b.Grow(b.write.AbsolutePos() + 1)
+	if !b.checkWritable("Write") || (b.maxElements > 0 && !b.checkRoom("Write", 1)) {
+		return
+	}
 	row, col := b.bufferPos(b.write)
+	if row >= len(b.buffers) {
+		b.Grow(b.write.AbsolutePos() - b.startPos().AbsolutePos() + 1)
+	}
 	b.buffers[row][col] = element
 	b.write = b.write.Move(1)
+	b.sample()
+	for _, w := range b.watchers {
+		w.fn(element)
+	}
+}
+
+// WriteSlice writes all elements in the provided slice to the Buffer. If needed the Buffer is grown to hold the
+// elements. The elements are copied row by row. Hence, WriteSlice is faster than writing the elements one by one
+// (see Buffer.Write).
+func (b *Buffer[T]) WriteSlice(elements []T) {
+	if b.maxElements > 0 && !b.checkRoom("WriteSlice", len(elements)) {
+		return
+	}
+	b.writeSlice(elements)
+}
+
+// writeSlice writes all elements in the provided slice to the Buffer. The elements are copied row by row.
+func (b *Buffer[T]) writeSlice(elements []T) {
+	if !b.checkWritable("Write") {
+		return
+	}
+	b.Grow(b.write.AbsolutePos() - b.startPos().AbsolutePos() + len(elements))
+	from := b.write
+	for len(elements) > 0 {
+		row, col := b.bufferPos(b.write)
+		n := copy(b.buffers[row][col:], elements)
+		elements = elements[n:]
+		b.write = b.write.Move(n)
+	}
+	b.sample()
+	b.notifyWatchers(from)
 }
 
 // State return a Buffer state. The state may be used to backtrack to the current state.
 func (b *Buffer[T]) State() State {
+	if pos := b.read.AbsolutePos(); b.pinned < 0 || pos < b.pinned {
+		b.pinned = pos
+	}
 	return newState(b.read, b.write)
 }
 
@@ -127,11 +390,23 @@ func (b *Buffer[T]) State() State {
 // anymore. If such the case an IllegalStateError is returned. To mitigate such errors it is recommended only
 // rollback to states created after the last commit.
 func (b *Buffer[T]) Rollback(state State) error {
+	from := b.read.AbsolutePos()
+	err := b.rollback(state)
+	if err != nil {
+		err = b.misused("Rollback", err, true)
+	}
+	b.observe(Event{Kind: RollbackEvent, Elements: from - b.read.AbsolutePos(), Err: err})
+	return err
+}
+
+func (b *Buffer[T]) rollback(state State) error {
 	if !state.init {
 		return ZeroStateError
 	}
-	// Check if state is still valid (not created before a call to commit)
-	if state.read.Row < b.startRow {
+	// Check if state is still valid (not created before a call to commit or after discarded elements) and
+	// created by a Buffer with the same geometry
+	if state.read.rowSize != b.rowSize || state.read.Row < b.startRow ||
+		state.read.AbsolutePos() > b.write.AbsolutePos() {
 		return IllegalStateError
 	}
 	b.read = state.read
@@ -139,27 +414,151 @@ func (b *Buffer[T]) Rollback(state State) error {
 }
 
 // Commit will remove consumed elements from the Buffer mitigating the Buffer to grow indefinitely. Technically
-// Commit removes buffer rows before the current read pointer.
+// Commit removes buffer rows before the current read pointer. If a commit finalizer is configured (see
+// Buffer.WithCommitFinalizer) then the finalizer is called for each removed element. Rows holding the position
+// of a live savepoint are not removed (see Buffer.Savepoint).
 func (b *Buffer[T]) Commit() {
+	b.trim(b.retainRow())
+}
+
+// CommitKeep will remove consumed elements from the Buffer like Buffer.Commit but keeps the keepRows rows before
+// the row holding the read position. Hence, states created at most keepRows * row size elements before the
+// current read position remain valid after the commit. If keepRows is < 0 then a panic is raised.
+func (b *Buffer[T]) CommitKeep(keepRows int) {
+	if keepRows < 0 {
+		panic(argumentError{"illegal negative number of kept rows", keepRows})
+	}
+	b.trim(max(min(b.retainRow(), b.read.Row-keepRows), b.startRow))
+}
+
+// retainRow returns the first row that must be retained by Buffer.Commit. That is, the row holding the read
+// position or the row holding the oldest live savepoint position or retained history (if before the read
+// position).
+func (b *Buffer[T]) retainRow() int {
+	row := max(b.read.Move(-b.history).Row, b.startRow)
+	for _, s := range b.savepoints {
+		row = min(row, s.state.read.Row)
+	}
+	for _, c := range b.cursors {
+		row = min(row, c.commit.Row)
+	}
+	return row
+}
+
+// trim removes the Buffer rows before the specified row (see Buffer.Commit).
+func (b *Buffer[T]) trim(to int) {
 	// Cleanup unreachable Buffer rows
-	row, _ := b.bufferPos(b.read)
-	b.buffers = b.buffers[row-b.startRow:]
+	row := to - b.startRow
+	if b.onConsumed != nil {
+		b.release(b.startPos().Move(row * b.rowSize).AbsolutePos())
+	}
+	if b.commitFinalizer != nil {
+		b.chunks(b.startPos(), position{rowSize: b.rowSize, Row: to}, func(chunk []T) bool {
+			for _, element := range chunk {
+				b.commitFinalizer(element)
+			}
+			return true
+		})
+	}
+	b.buffers = b.buffers[row:]
 	b.startRow += row
+	b.trimSeqMarks()
+	if b.storage != nil {
+		b.storage.TrimBefore(b.startRow)
+	}
+	// States before the new first row are no longer valid
+	if start := b.startPos().AbsolutePos(); b.pinned >= 0 && b.pinned < start {
+		b.pinned = start
+	}
+	b.observe(Event{Kind: CommitEvent, Rows: row, Elements: row * b.rowSize})
+}
+
+// WithCommitFinalizer configures a finalizer called for each element permanently removed from the Buffer by
+// Buffer.Commit. Elements discarded by Buffer.Truncate, Buffer.ConsumeBack, Buffer.WriteFront and Buffer.Reset
+// are passed to the finalizer as well. The finalizer is called exactly once for each removed element, and only
+// for elements that have been written to the Buffer. The Buffer is returned to allow chaining.
+func (b *Buffer[T]) WithCommitFinalizer(fn func(T)) *Buffer[T] {
+	b.commitFinalizer = fn
+	return b
 }
 
-// Grow will grow the Buffer to be able to hold at least the specified number of elements.
+// Grow will grow the Buffer to be able to hold at least the specified number of elements. The size includes
+// the elements still available in the Buffer (that is, not removed by Buffer.Commit).
 func (b *Buffer[T]) Grow(size int) {
-	rows := size / b.rowSize
-	for i := len(b.buffers); i <= rows; i++ {
-		b.buffers = append(b.buffers, make([]T, b.rowSize))
+	rows := (size + b.rowSize - 1) / b.rowSize
+	if rows <= len(b.buffers) {
+		return
+	}
+	added := rows - len(b.buffers)
+	for i := len(b.buffers); i < rows; i++ {
+		b.buffers = append(b.buffers, b.newRow(b.startRow+i))
+	}
+	b.observe(Event{Kind: GrowEvent, Rows: added})
+}
+
+// Prewarm allocates the specified number of rows after the row holding the write position (unless already
+// allocated) and touches all elements not yet written. If rows is < 0 then a panic is raised.
+func (b *Buffer[T]) Prewarm(rows int) {
+	if rows < 0 {
+		panic(argumentError{"illegal negative number of rows", rows})
+	}
+	b.Grow((b.write.Row - b.startRow + 1 + rows) * b.rowSize)
+	row, col := b.bufferPos(b.write)
+	clear(b.buffers[row][col:])
+	for _, elements := range b.buffers[row+1:] {
+		clear(elements)
 	}
 }
 
 // Buffered returns the number of unconsumed elements in the Buffer.
 func (b *Buffer[T]) Buffered() int {
+	// Fast path when all unconsumed elements are in the same row
+	if b.read.Row == b.write.Row {
+		return b.write.Col - b.read.Col
+	}
 	return b.write.AbsolutePos() - b.read.AbsolutePos()
 }
 
+// peek returns the next n unconsumed elements without consuming them. If the elements are held in a single Buffer
+// row then a slice referencing the row is returned. Otherwise, the elements are copied to the scratch slice
+// (reallocated if needed) and the copy is returned. The caller must make sure that there are at least n
+// unconsumed elements in the Buffer.
+func (b *Buffer[T]) peek(n int, scratch *[]T) []T {
+	if n == 0 {
+		return (*scratch)[:0]
+	}
+	row, col := b.bufferPos(b.read)
+	if col+n <= b.rowSize {
+		return b.buffers[row][col : col+n : col+n]
+	}
+	s := (*scratch)[:0]
+	b.chunks(b.read, b.read.Move(n), func(chunk []T) bool {
+		s = append(s, chunk...)
+		return true
+	})
+	*scratch = s
+	return s
+}
+
+// startPos returns the position of the first element still available in the Buffer.
+func (b *Buffer[T]) startPos() position {
+	return position{rowSize: b.rowSize, Row: b.startRow}
+}
+
+// TotalWritten returns the number of elements ever written to the Buffer. The count is monotonically increasing
+// and not affected by Buffer.Commit or by discarding elements (see Buffer.Truncate).
+func (b *Buffer[T]) TotalWritten() uint64 {
+	return uint64(b.write.AbsolutePos() + b.seqOffset)
+}
+
+// TotalConsumed returns the number of elements ever consumed from the Buffer. The count is not affected by
+// Buffer.Commit but is decreased by Buffer.Rollback as rolled back elements are to be consumed again. Note that
+// the lag of the Buffer (TotalWritten - TotalConsumed) is equal to Buffer.Buffered unless elements have been
+// discarded (see Buffer.Truncate) or written to the front (see Buffer.WriteFront).
+func (b *Buffer[T]) TotalConsumed() uint64 {
+	return uint64(b.read.AbsolutePos())
+}
+
 func (b *Buffer[T]) bufferPos(pos position) (row, col int) {
 	return pos.Row - b.startRow, pos.Col
 }
@@ -172,19 +571,33 @@ func New[T any]() (buf *Buffer[T]) {
 
 // NewWithSize creates a new Buffer with the specified row size. The Buffer is pre-allocated with the specified
 // number of rows. If row size or number of rows is <= 0 then a panic is raised.
+//
+// If the row size is at most 16 then the first row is allocated together with the Buffer. Hence, a small
+// short-lived Buffer created with a single row (e.g. NewWithSize[T](8, 1)) needs a single allocation as long as it
+// doesn't grow. Buffers with larger rows don't hold any embedded row.
 func NewWithSize[T any](rowSize, rows int) (buf *Buffer[T]) {
 	if rowSize <= 0 {
-		panic(fmt.Errorf("illegal non-positive row size %d", rowSize))
+		panic(argumentError{"illegal non-positive row size", rowSize})
 	}
 	if rows <= 0 {
-		panic(fmt.Errorf("illegal non-positive number of rows %d", rowSize))
+		panic(argumentError{"illegal non-positive number of rows", rows})
 	}
-	buf = &Buffer[T]{
-		rowSize: rowSize,
-		buffers: make([][]T, 0, rows),
-		read:    position{rowSize: rowSize},
-		write:   position{rowSize: rowSize},
+	if rowSize <= embeddedRowSize {
+		// Use the embedded first row (and the embedded row list if a single row is pre-allocated)
+		small := &smallBuffer[T]{}
+		small.rows[0] = small.row[:rowSize:rowSize]
+		buf = &small.Buffer
+		buf.buffers = small.rows[:1:1]
+		if rows > 1 {
+			buf.buffers = append(make([][]T, 0, rows), buf.buffers...)
+		}
+	} else {
+		buf = &Buffer[T]{buffers: make([][]T, 0, rows)}
 	}
+	buf.rowSize = rowSize
+	buf.read = position{rowSize: rowSize}
+	buf.write = position{rowSize: rowSize}
+	buf.pinned = -1
 	buf.Grow(rows * rowSize)
 	return
 }
@@ -2,6 +2,8 @@ package gobuffer
 
 import (
 	"errors"
+	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -229,6 +231,28 @@ func TestBuffer(t *testing.T) {
 				opNextNotOk{},
 			},
 		},
+		{
+			"read after multiple commits", []any{
+				opWrites[rune]{Elem: 'a', Num: 8},
+				opConsumes{Num: 6},
+				opCommit{},
+				opWrite[rune]{Elem: 'b'},
+				opWrite[rune]{Elem: 'c'},
+				opWrite[rune]{Elem: 'd'},
+				opWrite[rune]{Elem: 'e'},
+				opNextAndConsume[rune]{Exp: 'a'},
+				opNextAndConsume[rune]{Exp: 'a'},
+				opNextAndConsume[rune]{Exp: 'b'},
+				opNextAndConsume[rune]{Exp: 'c'},
+				opCommit{},
+				opNextAndConsume[rune]{Exp: 'd'},
+				opState{},
+				opNextAndConsume[rune]{Exp: 'e'},
+				opRollback{},
+				opNextAndConsume[rune]{Exp: 'e'},
+				opNextNotOk{},
+			},
+		},
 		{
 			"state and rollback", []any{
 				opWrite[rune]{Elem: 'a'},
@@ -446,3 +470,410 @@ type opCommit struct{}
 type opBuffered struct {
 	Exp int
 }
+
+func TestBufferNextSeq(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	if _, _, ok := buf.NextSeq(); ok {
+		t.Errorf("unexpected next seq ok for empty buffer")
+	}
+	for _, r := range "abcdef" {
+		buf.Write(r)
+	}
+	for i := 0; i < 3; i++ {
+		buf.Consume()
+	}
+	buf.Commit()
+	buf.Consume()
+	r, seq, ok := buf.NextSeq()
+	if !ok || r != 'e' || seq != 4 {
+		t.Errorf("unexpected next seq:\nexp=%c/%d\ngot=%c/%d (%v)", 'e', 4, r, seq, ok)
+	}
+}
+
+func TestBufferNextSeq_Discarded(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abc" {
+		buf.Write(r)
+	}
+	buf.Truncate(1)
+	buf.Write('d')
+	buf.Write('e')
+	buf.ConsumeBack()
+	buf.Write('f')
+	buf.Pop()
+	buf.WriteFront('x')
+	// The sequence numbers are never reused and the written count never decreases
+	var got []uint64
+	for r, seq, ok := buf.NextSeq(); ok; r, seq, ok = buf.NextSeq() {
+		got = append(got, seq)
+		buf.Consume()
+		if r == 'd' {
+			buf.Commit()
+		}
+	}
+	if exp := []uint64{6, 3, 5}; !slices.Equal(got, exp) {
+		t.Errorf("unexpected sequence numbers:\nexp=%v\ngot=%v", exp, got)
+	}
+	if n := buf.TotalWritten(); n != 7 {
+		t.Errorf("unexpected total written:\nexp=%d\ngot=%d", 7, n)
+	}
+	buf.Write('g')
+	if _, seq, _ := buf.NextSeq(); seq != 7 {
+		t.Errorf("unexpected sequence number:\nexp=%d\ngot=%d", 7, seq)
+	}
+}
+
+func TestBufferTotalWrittenConsumed(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abcde" {
+		buf.Write(r)
+	}
+	buf.Consume()
+	state := buf.State()
+	buf.Consume()
+	buf.Consume()
+	buf.Commit()
+	if n := buf.TotalWritten(); n != 5 {
+		t.Errorf("unexpected total written:\nexp=%d\ngot=%d", 5, n)
+	}
+	if n := buf.TotalConsumed(); n != 3 {
+		t.Errorf("unexpected total consumed:\nexp=%d\ngot=%d", 3, n)
+	}
+	if err := buf.Rollback(state); !errors.Is(err, IllegalStateError) {
+		t.Errorf("unexpected rollback error: %v", err)
+	}
+	buf.Write('f')
+	if lag := buf.TotalWritten() - buf.TotalConsumed(); lag != uint64(buf.Buffered()) {
+		t.Errorf("unexpected lag:\nexp=%d\ngot=%d", buf.Buffered(), lag)
+	}
+}
+
+func TestBufferWithCommitFinalizer(t *testing.T) {
+	var finalized []int
+	buf := NewWithSize[int](3, 1).WithCommitFinalizer(func(i int) {
+		finalized = append(finalized, i)
+	})
+	for i := 0; i < 8; i++ {
+		buf.Write(i)
+	}
+	for i := 0; i < 4; i++ {
+		buf.Consume()
+	}
+	buf.Commit()
+	if !slices.Equal(finalized, []int{0, 1, 2}) {
+		t.Errorf("unexpected finalized elements:\nexp=%v\ngot=%v", []int{0, 1, 2}, finalized)
+	}
+	// Committing again without consuming a full row finalizes nothing
+	buf.Consume()
+	buf.Commit()
+	if len(finalized) != 3 {
+		t.Errorf("unexpected finalized elements:\nexp=%v\ngot=%v", []int{0, 1, 2}, finalized)
+	}
+	for i := 0; i < 3; i++ {
+		buf.Consume()
+	}
+	buf.Commit()
+	if !slices.Equal(finalized, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("unexpected finalized elements:\nexp=%v\ngot=%v", []int{0, 1, 2, 3, 4, 5}, finalized)
+	}
+}
+
+func TestBufferWithCommitFinalizer_Discarded(t *testing.T) {
+	var finalized []int
+	buf := NewWithSize[int](3, 1).WithCommitFinalizer(func(i int) {
+		finalized = append(finalized, i)
+	})
+	for i := 0; i < 6; i++ {
+		buf.Write(i)
+	}
+	buf.Truncate(3)
+	buf.ConsumeBack()
+	buf.Write(9)
+	buf.ConsumeN(3)
+	buf.Write(10)
+	buf.Commit()
+	// Each element is finalized exactly once
+	exp := []int{3, 4, 5, 2, 0, 1, 9}
+	if !slices.Equal(finalized, exp) {
+		t.Errorf("unexpected finalized elements:\nexp=%v\ngot=%v", exp, finalized)
+	}
+}
+
+func TestBufferOnConsumed(t *testing.T) {
+	var released []int
+	buf := NewWithSize[int](4, 1).OnConsumed(func(i int) {
+		released = append(released, i)
+	})
+	for i := 0; i < 10; i++ {
+		buf.Write(i)
+	}
+	buf.Consume()
+	buf.Consume()
+	// Without any state the consumed elements may never be read again
+	if !slices.Equal(released, []int{0, 1}) {
+		t.Errorf("unexpected released elements:\nexp=%v\ngot=%v", []int{0, 1}, released)
+	}
+	state := buf.State()
+	for i := 0; i < 4; i++ {
+		buf.Consume()
+	}
+	// The state pins the elements after its read position
+	if !slices.Equal(released, []int{0, 1}) {
+		t.Errorf("unexpected released elements:\nexp=%v\ngot=%v", []int{0, 1}, released)
+	}
+	if err := buf.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		buf.Consume()
+	}
+	buf.Commit()
+	// The commit releases the removed row and conservatively pins the new first row
+	if !slices.Equal(released, []int{0, 1, 2, 3}) {
+		t.Errorf("unexpected released elements:\nexp=%v\ngot=%v", []int{0, 1, 2, 3}, released)
+	}
+	for i := 0; i < 3; i++ {
+		buf.Consume()
+	}
+	buf.Commit()
+	if !slices.Equal(released, []int{0, 1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("unexpected released elements:\nexp=%v\ngot=%v", []int{0, 1, 2, 3, 4, 5, 6, 7}, released)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	buf.Write('a')
+	a := buf.State()
+	for _, r := range "bcde" {
+		buf.Write(r)
+	}
+	buf.Consume()
+	buf.Consume()
+	buf.Consume()
+	b := buf.State()
+	consumed, written, err := Diff(a, b)
+	if err != nil || consumed != 3 || written != 4 {
+		t.Errorf("unexpected diff:\nexp=%d/%d/%v\ngot=%d/%d/%v", 3, 4, nil, consumed, written, err)
+	}
+	consumed, written, err = Diff(b, a)
+	if err != nil || consumed != -3 || written != -4 {
+		t.Errorf("unexpected diff:\nexp=%d/%d/%v\ngot=%d/%d/%v", -3, -4, nil, consumed, written, err)
+	}
+	if _, _, err = Diff(a, State{}); !errors.Is(err, ZeroStateError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", ZeroStateError, err)
+	}
+}
+
+func BenchmarkBufferSmallWindow(b *testing.B) {
+	buf := New[rune]()
+	for i := 0; i < b.N; i++ {
+		for _, r := range "x := 42" {
+			buf.Write(r)
+		}
+		for _, ok := buf.Next(); ok; _, ok = buf.Next() {
+			buf.Consume()
+		}
+		buf.Commit()
+	}
+}
+
+func TestNewWithSize_EmbeddedRow(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		buf := NewWithSize[rune](8, 1)
+		for _, r := range "x := 1" {
+			buf.Write(r)
+		}
+		for _, ok := buf.Next(); ok; _, ok = buf.Next() {
+			buf.Consume()
+		}
+	})
+	if allocs > 1 {
+		t.Errorf("unexpected allocations:\nexp=%d\ngot=%v", 1, allocs)
+	}
+	// Buffers with larger rows don't hold an embedded row
+	if size, exp := reflect.TypeFor[Buffer[[64]byte]]().Size(), reflect.TypeFor[Buffer[byte]]().Size(); size != exp {
+		t.Errorf("unexpected buffer size:\nexp=%d\ngot=%d", exp, size)
+	}
+	// The embedded row works as an ordinary row when the buffer grows
+	buf := NewWithSize[rune](2, 2)
+	for _, r := range "abcdefg" {
+		buf.Write(r)
+	}
+	buf.Consume()
+	buf.Commit()
+	if got := readAll(buf); got != "bcdefg" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "bcdefg", got)
+	}
+}
+
+func TestBufferCommitKeep(t *testing.T) {
+	tests := []struct {
+		name     string
+		keepRows int
+		valid    bool
+		rows     int
+	}{
+		{"keep none", 0, false, 2},
+		{"keep state row", 2, true, 4},
+		{"keep more than available", 10, true, 5},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := NewWithSize[int](3, 1)
+			for i := 0; i < 14; i++ {
+				buf.Write(i)
+			}
+			for i := 0; i < 5; i++ {
+				buf.Consume()
+			}
+			state := buf.State()
+			for i := 0; i < 5; i++ {
+				buf.Consume()
+			}
+			buf.CommitKeep(test.keepRows)
+			if rows := buf.RetentionReport().Rows; rows != test.rows {
+				t.Errorf("unexpected rows:\nexp=%d\ngot=%d", test.rows, rows)
+			}
+			if err := buf.Rollback(state); (err == nil) != test.valid {
+				t.Errorf("unexpected rollback error: %v", err)
+			}
+		})
+	}
+}
+
+func TestBufferPrewarm(t *testing.T) {
+	buf := NewWithSize[rune](4, 1)
+	for _, r := range "abc" {
+		buf.Write(r)
+	}
+	buf.Prewarm(2)
+	if n := len(buf.buffers); n != 3 {
+		t.Errorf("unexpected number of rows:\nexp=%d\ngot=%d", 3, n)
+	}
+	// Writing to the prewarmed rows doesn't grow the Buffer
+	for _, r := range "defghijkl" {
+		buf.Write(r)
+	}
+	if n := len(buf.buffers); n != 3 {
+		t.Errorf("unexpected number of rows:\nexp=%d\ngot=%d", 3, n)
+	}
+	if got := readAll(buf); got != "abcdefghijkl" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "abcdefghijkl", got)
+	}
+}
+
+func TestBufferWriteSlice(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		exp    string
+	}{
+		{"empty", []string{""}, ""},
+		{"within row", []string{"ab", "c"}, "abc"},
+		{"across rows", []string{"a", "bcdefghij", "klm"}, "abcdefghijklm"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := NewWithSize[rune](4, 1)
+			for _, chunk := range test.chunks {
+				buf.WriteSlice([]rune(chunk))
+			}
+			if got := readAll(buf); got != test.exp {
+				t.Errorf("unexpected content:\nexp=%s\ngot=%s", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestBufferPeekN(t *testing.T) {
+	buf := NewWithSize[rune](4, 1)
+	for _, r := range "abcdef" {
+		buf.Write(r)
+	}
+	buf.Next()
+	buf.Consume()
+	tests := []struct {
+		name string
+		n    int
+		exp  string
+		ok   bool
+	}{
+		{"none", 0, "", true},
+		{"within row", 2, "bc", true},
+		{"across rows", 5, "bcdef", true},
+		{"too many", 6, "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := buf.PeekN(test.n)
+			if string(got) != test.exp || ok != test.ok {
+				t.Errorf("unexpected peek:\nexp=%s, %v\ngot=%s, %v", test.exp, test.ok, string(got), ok)
+			}
+		})
+	}
+	if r, _ := buf.Next(); r != 'b' {
+		t.Errorf("unexpected next element:\nexp=%c\ngot=%c", 'b', r)
+	}
+}
+
+func TestBufferConsumeN(t *testing.T) {
+	buf := NewWithSize[rune](4, 1)
+	for _, r := range "abcdefg" {
+		buf.Write(r)
+	}
+	buf.ConsumeN(0)
+	buf.ConsumeN(2)
+	buf.ConsumeN(3)
+	if r, _ := buf.Next(); r != 'f' {
+		t.Errorf("unexpected next element:\nexp=%c\ngot=%c", 'f', r)
+	}
+	buf.ConsumeN(3)
+	if err := buf.Misuse(); !errors.Is(err, EmptyConsumeError) {
+		t.Errorf("unexpected misuse:\nexp=%v\ngot=%v", EmptyConsumeError, err)
+	}
+	if got := readAll(buf); got != "fg" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "fg", got)
+	}
+}
+
+func TestBufferDiscard(t *testing.T) {
+	buf := NewWithSize[rune](4, 1)
+	for _, r := range "abcdef" {
+		buf.Write(r)
+	}
+	tests := []struct {
+		n   int
+		exp int
+	}{
+		{0, 0},
+		{4, 4},
+		{5, 2},
+		{1, 0},
+	}
+	for _, test := range tests {
+		if got := buf.Discard(test.n); got != test.exp {
+			t.Errorf("unexpected discarded elements for %d:\nexp=%d\ngot=%d", test.n, test.exp, got)
+		}
+	}
+	if err := buf.Misuse(); err != nil {
+		t.Errorf("unexpected misuse: %v", err)
+	}
+}
+
+func TestBufferPop(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abc" {
+		buf.Write(r)
+	}
+	var got []rune
+	for r, ok := buf.Pop(); ok; r, ok = buf.Pop() {
+		got = append(got, r)
+	}
+	if string(got) != "abc" {
+		t.Errorf("unexpected elements:\nexp=%s\ngot=%s", "abc", string(got))
+	}
+	if n := buf.Buffered(); n != 0 {
+		t.Errorf("unexpected buffered elements:\nexp=%d\ngot=%d", 0, n)
+	}
+}
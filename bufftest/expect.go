@@ -0,0 +1,63 @@
+package bufftest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/habak67/gobuffer"
+)
+
+// ExpectSequence checks that the unconsumed elements in the Buffer are equal to the wanted elements. Elements are
+// compared using eq. If eq is nil then the elements are compared using reflect.DeepEqual. The Buffer is not
+// modified (the elements are peeked, not consumed).
+//
+// If the elements differ then the test is marked as failed with a readable diff listing the wanted and buffered
+// elements side by side with the differing rows marked.
+func ExpectSequence[T any](t testing.TB, buf *gobuffer.Buffer[T], want []T, eq func(a, b T) bool) {
+	t.Helper()
+	if eq == nil {
+		eq = func(a, b T) bool { return reflect.DeepEqual(a, b) }
+	}
+	var got []T
+	for chunk := range buf.Chunks() {
+		got = append(got, chunk...)
+	}
+	first := -1
+	for i := 0; i < max(len(want), len(got)); i++ {
+		if i >= len(want) || i >= len(got) || !eq(want[i], got[i]) {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return
+	}
+	t.Errorf("unexpected buffered sequence (first difference at index %d):\n%s", first, sequenceDiff(want, got, eq))
+}
+
+// sequenceDiff formats the wanted and got elements side by side marking the differing rows.
+func sequenceDiff[T any](want, got []T, eq func(a, b T) bool) string {
+	format := func(s []T, i int) string {
+		if i >= len(s) {
+			return "<missing>"
+		}
+		return fmt.Sprintf("%v", s[i])
+	}
+	rows := max(len(want), len(got))
+	width := len("exp")
+	for i := 0; i < rows; i++ {
+		width = max(width, len(format(want, i)))
+	}
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "    %4s  %-*s  %s\n", "idx", width, "exp", "got")
+	for i := 0; i < rows; i++ {
+		marker := " "
+		if i >= len(want) || i >= len(got) || !eq(want[i], got[i]) {
+			marker = ">"
+		}
+		_, _ = fmt.Fprintf(&sb, "  %s %4d  %-*s  %s\n", marker, i, width, format(want, i), format(got, i))
+	}
+	return sb.String()
+}
@@ -0,0 +1,57 @@
+package bufftest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/habak67/gobuffer"
+)
+
+// recorder is a testing.TB recording reported errors.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestExpectSequence(t *testing.T) {
+	buf := gobuffer.NewWithSize[string](2, 1)
+	for _, s := range []string{"let", "x", "=", "1"} {
+		buf.Write(s)
+	}
+	buf.Consume()
+	tests := []struct {
+		name string
+		want []string
+		diff string
+	}{
+		{"equal", []string{"x", "=", "1"}, ""},
+		{"different", []string{"x", "==", "1"}, "  >    1  ==   =\n"},
+		{"missing", []string{"x", "=", "1", ";"}, "  >    3  ;    <missing>\n"},
+		{"extra", []string{"x", "="}, "  >    2  <missing>  1\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := &recorder{TB: t}
+			ExpectSequence(rec, buf, test.want, nil)
+			if test.diff == "" {
+				if len(rec.errors) != 0 {
+					t.Errorf("unexpected errors: %v", rec.errors)
+				}
+				return
+			}
+			if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], test.diff) {
+				t.Errorf("unexpected diff:\nexp=%q\ngot=%q", test.diff, rec.errors)
+			}
+		})
+	}
+	if n := buf.Buffered(); n != 3 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 3, n)
+	}
+}
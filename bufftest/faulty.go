@@ -0,0 +1,178 @@
+// Package bufftest provides utilities for testing code using gobuffer buffers.
+package bufftest
+
+import (
+	"errors"
+
+	"github.com/habak67/gobuffer"
+)
+
+var InjectedError = errors.New("injected failure")
+
+var _ gobuffer.Writer[any] = (*Faulty[any])(nil)
+var _ gobuffer.Source[any] = (*FaultySource[any])(nil)
+
+// Faults configures the failures injected by a Faulty buffer. Each kind of failure is injected every k-th call of
+// the corresponding operation (counting from the first call). A zero interval disables the failure.
+type Faults struct {
+	// WriteEvery makes every k-th call to Faulty.Write or Faulty.TryWrite fail without writing the element.
+	// Faulty.TryWrite returns WriteErr for a failed write.
+	WriteEvery int
+	// WriteErr holds the error returned by failing writes. If nil then InjectedError is used.
+	WriteErr error
+	// NextEvery makes every k-th call to Faulty.Next report that there is no next element even if the Buffer
+	// holds unconsumed elements. This simulates a producer not keeping up with the consumer.
+	NextEvery int
+	// RollbackEvery makes every k-th call to Faulty.Rollback fail with RollbackErr without rolling back.
+	RollbackEvery int
+	// RollbackErr holds the error returned by failing rollbacks. If nil then InjectedError is used.
+	RollbackErr error
+	// ReadEvery makes every k-th call to FaultySource.Read fail with ReadErr without reading any elements.
+	ReadEvery int
+	// ReadErr holds the error returned by failing reads. If nil then InjectedError is used.
+	ReadErr error
+}
+
+// Faulty wraps a gobuffer.Buffer and injects failures into Write, Next and Rollback as configured. A Faulty buffer
+// lets applications test their error paths around the buffer without elaborate mocks.
+//
+// Failures may also be injected on demand using Faulty.FailWrite and Faulty.FailRollback.
+type Faulty[T any] struct {
+	buf       *gobuffer.Buffer[T]
+	faults    Faults
+	writes    int
+	nexts     int
+	rollbacks int
+	writeErr  error // writeErr holds an on demand failure for the next write.
+	rbErr     error // rbErr holds an on demand failure for the next rollback.
+}
+
+// NewFaulty creates a Faulty buffer wrapping the provided Buffer.
+func NewFaulty[T any](buf *gobuffer.Buffer[T], faults Faults) *Faulty[T] {
+	if faults.WriteErr == nil {
+		faults.WriteErr = InjectedError
+	}
+	if faults.RollbackErr == nil {
+		faults.RollbackErr = InjectedError
+	}
+	return &Faulty[T]{buf: buf, faults: faults}
+}
+
+// Buffer returns the wrapped Buffer.
+func (f *Faulty[T]) Buffer() *gobuffer.Buffer[T] {
+	return f.buf
+}
+
+// FailWrite makes the next call to Faulty.Write or Faulty.TryWrite fail with the provided error.
+func (f *Faulty[T]) FailWrite(err error) {
+	f.writeErr = err
+}
+
+// FailRollback makes the next call to Faulty.Rollback fail with the provided error.
+func (f *Faulty[T]) FailRollback(err error) {
+	f.rbErr = err
+}
+
+// Write writes an element to the Buffer unless a failure is injected. A failed write silently drops the element
+// (like a lost write). Use Faulty.TryWrite to observe the injected failures.
+func (f *Faulty[T]) Write(element T) {
+	_ = f.TryWrite(element)
+}
+
+// TryWrite writes an element to the Buffer unless a failure is injected. The error of a failed write is returned.
+// Errors of the Buffer (see gobuffer.Buffer.TryWrite) are returned as well.
+func (f *Faulty[T]) TryWrite(element T) error {
+	f.writes++
+	if err := f.writeErr; err != nil {
+		f.writeErr = nil
+		return err
+	}
+	if every(f.writes, f.faults.WriteEvery) {
+		return f.faults.WriteErr
+	}
+	return f.buf.TryWrite(element)
+}
+
+// Next returns the next element from the Buffer unless a failure is injected.
+func (f *Faulty[T]) Next() (element T, ok bool) {
+	f.nexts++
+	if every(f.nexts, f.faults.NextEvery) {
+		return
+	}
+	return f.buf.Next()
+}
+
+// Consume consumes the next element in the Buffer.
+func (f *Faulty[T]) Consume() {
+	f.buf.Consume()
+}
+
+// State returns a Buffer state.
+func (f *Faulty[T]) State() gobuffer.State {
+	return f.buf.State()
+}
+
+// Rollback rolls back the Buffer to the provided state unless a failure is injected.
+func (f *Faulty[T]) Rollback(state gobuffer.State) error {
+	f.rollbacks++
+	if err := f.rbErr; err != nil {
+		f.rbErr = nil
+		return err
+	}
+	if every(f.rollbacks, f.faults.RollbackEvery) {
+		return f.faults.RollbackErr
+	}
+	return f.buf.Rollback(state)
+}
+
+// Commit commits the Buffer.
+func (f *Faulty[T]) Commit() {
+	f.buf.Commit()
+}
+
+// Buffered returns the number of unconsumed elements in the Buffer.
+func (f *Faulty[T]) Buffered() int {
+	return f.buf.Buffered()
+}
+
+// FaultySource wraps a gobuffer.Source and injects failures into Read as configured (see Faults.ReadEvery). A
+// FaultySource lets applications test the handling of source errors (like gobuffer.Buffer.WithRetry).
+//
+// Failures may also be injected on demand using FaultySource.FailRead.
+type FaultySource[T any] struct {
+	src     gobuffer.Source[T]
+	faults  Faults
+	reads   int
+	readErr error // readErr holds an on demand failure for the next read.
+}
+
+// NewFaultySource creates a FaultySource wrapping the provided source. Only the read faults of the provided
+// Faults are used.
+func NewFaultySource[T any](src gobuffer.Source[T], faults Faults) *FaultySource[T] {
+	if faults.ReadErr == nil {
+		faults.ReadErr = InjectedError
+	}
+	return &FaultySource[T]{src: src, faults: faults}
+}
+
+// FailRead makes the next call to FaultySource.Read fail with the provided error.
+func (s *FaultySource[T]) FailRead(err error) {
+	s.readErr = err
+}
+
+// Read reads elements from the wrapped source unless a failure is injected.
+func (s *FaultySource[T]) Read(p []T) (int, error) {
+	s.reads++
+	if err := s.readErr; err != nil {
+		s.readErr = nil
+		return 0, err
+	}
+	if every(s.reads, s.faults.ReadEvery) {
+		return 0, s.faults.ReadErr
+	}
+	return s.src.Read(p)
+}
+
+func every(n, k int) bool {
+	return k > 0 && n%k == 0
+}
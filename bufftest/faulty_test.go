@@ -0,0 +1,97 @@
+package bufftest
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/habak67/gobuffer"
+)
+
+func TestFaulty(t *testing.T) {
+	full := errors.New("full")
+	buf := NewFaulty(gobuffer.New[int](), Faults{WriteEvery: 3, WriteErr: full, NextEvery: 2, RollbackEvery: 1})
+	var errs []error
+	for i := 0; i < 6; i++ {
+		errs = append(errs, buf.TryWrite(i))
+	}
+	for i, err := range errs {
+		var exp error
+		if i%3 == 2 {
+			exp = full
+		}
+		if !errors.Is(err, exp) {
+			t.Errorf("[%d] unexpected write error:\nexp=%v\ngot=%v", i, exp, err)
+		}
+	}
+	if n := buf.Buffered(); n != 4 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 4, n)
+	}
+	if _, ok := buf.Next(); !ok {
+		t.Errorf("unexpected next not ok")
+	}
+	if _, ok := buf.Next(); ok {
+		t.Errorf("expected injected next not ok")
+	}
+	if err := buf.Rollback(buf.State()); !errors.Is(err, InjectedError) {
+		t.Errorf("unexpected rollback error:\nexp=%v\ngot=%v", InjectedError, err)
+	}
+}
+
+func TestFaulty_OnDemand(t *testing.T) {
+	buf := NewFaulty(gobuffer.New[int](), Faults{})
+	failure := errors.New("failure")
+	buf.FailWrite(failure)
+	if err := buf.TryWrite(1); !errors.Is(err, failure) {
+		t.Errorf("unexpected write error:\nexp=%v\ngot=%v", failure, err)
+	}
+	buf.Write(2)
+	buf.FailWrite(failure)
+	buf.Write(3)
+	state := buf.State()
+	buf.Consume()
+	buf.FailRollback(failure)
+	if err := buf.Rollback(state); !errors.Is(err, failure) {
+		t.Errorf("unexpected rollback error:\nexp=%v\ngot=%v", failure, err)
+	}
+	if err := buf.Rollback(state); err != nil {
+		t.Errorf("unexpected rollback error: %v", err)
+	}
+	if v, ok := buf.Next(); !ok || v != 2 {
+		t.Errorf("unexpected next:\nexp=%d\ngot=%d (%v)", 2, v, ok)
+	}
+}
+
+func TestFaultySource(t *testing.T) {
+	elements := []int{1, 2, 3}
+	src := NewFaultySource[int](gobuffer.SourceFunc[int](func(p []int) (int, error) {
+		if len(elements) == 0 {
+			return 0, io.EOF
+		}
+		p[0] = elements[0]
+		elements = elements[1:]
+		return 1, nil
+	}), Faults{ReadEvery: 2})
+	buf := gobuffer.NewFromSource[int](src).WithRetry(gobuffer.RetryPolicy{MaxAttempts: 2})
+	var got []int
+	for v, ok := buf.Next(); ok; v, ok = buf.Next() {
+		got = append(got, v)
+		buf.Consume()
+	}
+	if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("unexpected elements:\nexp=%v\ngot=%v", []int{1, 2, 3}, got)
+	}
+	if err := buf.Err(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// An on demand failure stops a Buffer without retries
+	failure := errors.New("failure")
+	src.FailRead(failure)
+	buf = gobuffer.NewFromSource[int](src)
+	if _, ok := buf.Next(); ok {
+		t.Errorf("unexpected next element")
+	}
+	if err := buf.Err(); !errors.Is(err, failure) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", failure, err)
+	}
+}
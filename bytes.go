@@ -0,0 +1,268 @@
+package gobuffer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+)
+
+var IllegalSeekError = errors.New("seek position outside retained window")
+
+// ByteBuffer is a Buffer holding bytes. Besides the ordinary Buffer methods a ByteBuffer provides methods mirroring
+// the semantics of bufio.Reader. Code written against bufio.Reader may therefore switch to a ByteBuffer and gain
+// rollback and commit.
+//
+// A ByteBuffer doesn't read from an underlying reader. Hence, when the methods mirroring bufio.Reader run out of
+// buffered bytes io.EOF is returned.
+type ByteBuffer struct {
+	*Buffer[byte]
+	scratch []byte // scratch holds bytes spanning multiple Buffer rows.
+}
+
+// NewByteBuffer creates a new ByteBuffer.
+func NewByteBuffer() *ByteBuffer {
+	return &ByteBuffer{Buffer: New[byte]()}
+}
+
+// NewByteBufferWithSize creates a new ByteBuffer with the specified row size and number of pre-allocated rows
+// (see NewWithSize).
+func NewByteBufferWithSize(rowSize, rows int) *ByteBuffer {
+	return &ByteBuffer{Buffer: NewWithSize[byte](rowSize, rows)}
+}
+
+// Peek returns the next n bytes without consuming them. If Peek returns fewer than n bytes then io.EOF is
+// returned. If n < 0 then bufio.ErrNegativeCount is returned.
+//
+// The returned slice is only valid until the next call to a method of the ByteBuffer.
+func (b *ByteBuffer) Peek(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, bufio.ErrNegativeCount
+	}
+	var err error
+	if buffered := b.Buffered(); n > buffered {
+		n, err = buffered, io.EOF
+	}
+	return b.peek(n, &b.scratch), err
+}
+
+// Read reads (consumes) up to len(p) buffered bytes into p (see io.Reader). If the ByteBuffer has no buffered
+// bytes then io.EOF is returned. Hence, a ByteBuffer may be handed to any code reading from an io.Reader (like
+// json.Decoder).
+func (b *ByteBuffer) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if b.Buffered() == 0 {
+		return 0, io.EOF
+	}
+	b.chunks(b.read, b.read.Move(min(len(p), b.Buffered())), func(chunk []byte) bool {
+		n += copy(p[n:], chunk)
+		return true
+	})
+	b.consume(n)
+	return n, nil
+}
+
+// ReadByte reads (consumes) the next buffered byte (see io.ByteReader). If the ByteBuffer has no buffered bytes
+// then io.EOF is returned.
+func (b *ByteBuffer) ReadByte() (byte, error) {
+	if b.Buffered() == 0 {
+		return 0, io.EOF
+	}
+	row, col := b.bufferPos(b.read)
+	c := b.buffers[row][col]
+	b.consume(1)
+	return c, nil
+}
+
+// UnreadByte unreads the last consumed byte (see io.ByteScanner). Unlike bufio.Reader any consumed byte still
+// retained by the ByteBuffer may be unread. That is, UnreadByte may be called repeatedly and after any method
+// consuming bytes. If the last consumed byte isn't retained (e.g. removed by Buffer.Commit) then
+// bufio.ErrInvalidUnreadByte is returned.
+func (b *ByteBuffer) UnreadByte() error {
+	pos := b.read.AbsolutePos() - 1
+	if pos < b.startPos().AbsolutePos() || (b.onConsumed != nil && pos < b.released) {
+		return bufio.ErrInvalidUnreadByte
+	}
+	b.read = b.read.Move(-1)
+	return nil
+}
+
+// Discard skips (consumes) the next n bytes returning the number of bytes discarded. If Discard skips fewer than
+// n bytes then io.EOF is returned. If n < 0 then bufio.ErrNegativeCount is returned.
+func (b *ByteBuffer) Discard(n int) (discarded int, err error) {
+	if n < 0 {
+		return 0, bufio.ErrNegativeCount
+	}
+	if buffered := b.Buffered(); n > buffered {
+		n, err = buffered, io.EOF
+	}
+	b.consume(n)
+	return n, err
+}
+
+// ReadSlice reads (consumes) until the first occurrence of delim returning the bytes up to and including the
+// delimiter. If ReadSlice doesn't find the delimiter then all buffered bytes are returned together with io.EOF.
+//
+// The returned slice is only valid until the next call to a method of the ByteBuffer.
+func (b *ByteBuffer) ReadSlice(delim byte) (line []byte, err error) {
+	n := 0
+	found := false
+	for chunk := range b.readChunks() {
+		if i := bytes.IndexByte(chunk, delim); i >= 0 {
+			n += i + 1
+			found = true
+			break
+		}
+		n += len(chunk)
+	}
+	if !found {
+		err = io.EOF
+	}
+	line = b.peek(n, &b.scratch)
+	b.consume(n)
+	return
+}
+
+// Seek implements io.Seeker moving the read position of the ByteBuffer. Offsets are absolute positions in the
+// stream of bytes ever written to the ByteBuffer (see Buffer.NextSeq). That is, io.SeekStart is relative to the
+// first byte ever written, io.SeekCurrent is relative to the next byte to read and io.SeekEnd is relative to the
+// position after the last written byte. The new absolute read position is returned.
+//
+// Seeking is restricted to the retained window of the ByteBuffer, that is, the bytes not yet removed by
+// Buffer.Commit and the position after the last written byte. If a consumed hook is configured (see
+// Buffer.OnConsumed) then seeking is further restricted to bytes not yet passed to the hook. If the target
+// position is outside the retained window then an error wrapping IllegalSeekError is returned and the read
+// position is not changed.
+func (b *ByteBuffer) Seek(offset int64, whence int) (int64, error) {
+	var base int64
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		base = int64(b.read.AbsolutePos())
+	case io.SeekEnd:
+		base = int64(b.write.AbsolutePos())
+	default:
+		return 0, detailError{err: IllegalSeekError, detail: "illegal whence " + strconv.Itoa(whence)}
+	}
+	target := base + offset
+	start, end := int64(max(b.startPos().AbsolutePos(), b.released)), int64(b.write.AbsolutePos())
+	if target < start || target > end {
+		return 0, detailError{err: IllegalSeekError, detail: "position " + strconv.FormatInt(target, 10) + " not in [" +
+			strconv.FormatInt(start, 10) + ", " + strconv.FormatInt(end, 10) + "]"}
+	}
+	if n := int(target) - b.read.AbsolutePos(); n > 0 {
+		b.consume(n)
+	} else {
+		b.read = b.read.Move(n)
+	}
+	return target, nil
+}
+
+// byteWriter is an io.Writer writing to a byte Buffer (see AsWriter).
+type byteWriter struct {
+	buf *Buffer[byte]
+}
+
+// AsWriter returns an io.Writer writing to the provided Buffer (or the Buffer of a ByteBuffer). The written bytes
+// are copied row by row (see Buffer.WriteSlice).
+//
+// Writing to a frozen Buffer (see Buffer.Freeze) returns FrozenError (or panics according to the misuse policy,
+// see MisusePolicy). If the Buffer can't hold all written bytes (see Buffer.WithMaxElements) then the bytes that
+// fit are written and BufferFullError is returned.
+func AsWriter(buf *Buffer[byte]) io.Writer {
+	return byteWriter{buf: buf}
+}
+
+func (w byteWriter) Write(p []byte) (int, error) {
+	if w.buf.frozen {
+		return 0, w.buf.misused("Write", FrozenError, true)
+	}
+	if w.buf.maxElements > 0 && w.buf.room() < len(p) {
+		// Write as much as fits (see Buffer.WithMaxElements)
+		n := max(w.buf.room(), 0)
+		w.buf.writeSlice(p[:n])
+		return n, w.buf.full()
+	}
+	w.buf.writeSlice(p)
+	return len(p), nil
+}
+
+func (w byteWriter) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(w.buf, r)
+}
+
+// ReadFrom writes the bytes read from the reader to the ByteBuffer until io.EOF (see io.ReaderFrom). The bytes
+// are read directly into the Buffer rows. Hence, io.Copy to a ByteBuffer (or a writer returned by AsWriter)
+// doesn't allocate besides growing the Buffer. The number of written bytes is returned. Any error except io.EOF
+// returned by the reader is returned.
+//
+// Writing to a frozen Buffer (see Buffer.Freeze) returns FrozenError (or panics according to the misuse policy,
+// see MisusePolicy). If the Buffer is full (see Buffer.WithMaxElements) then reading stops and BufferFullError is
+// returned.
+func (b *ByteBuffer) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(b.Buffer, r)
+}
+
+// WriteTo writes (and consumes) all buffered bytes to the writer (see io.WriterTo). The bytes are written row by
+// row directly from the Buffer rows and are consumed as they are written. The number of written bytes is returned.
+// If the writer returns an error (or io.ErrShortWrite if it writes fewer bytes than requested) then the writing
+// stops and the error is returned. Only the bytes actually written are consumed.
+func (b *ByteBuffer) WriteTo(w io.Writer) (n int64, err error) {
+	err = b.DrainTo(func(chunk []byte) error {
+		written, err := w.Write(chunk)
+		n += int64(written)
+		if err == nil && written < len(chunk) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			b.consume(written)
+		}
+		return err
+	})
+	return
+}
+
+// readFrom writes the bytes read from the reader to the Buffer until io.EOF (see ByteBuffer.ReadFrom).
+func readFrom(buf *Buffer[byte], r io.Reader) (n int64, err error) {
+	if buf.frozen {
+		return 0, buf.misused("ReadFrom", FrozenError, true)
+	}
+	from := buf.write
+	defer func() {
+		buf.sample()
+		buf.notifyWatchers(from)
+	}()
+	for empty := 0; ; {
+		row, col := buf.bufferPos(buf.write)
+		if row >= len(buf.buffers) {
+			buf.Grow(buf.write.AbsolutePos() - buf.startPos().AbsolutePos() + 1)
+		}
+		dst := buf.buffers[row][col:]
+		if buf.maxElements > 0 {
+			room := buf.room()
+			if room <= 0 {
+				return n, buf.full()
+			}
+			dst = dst[:min(len(dst), room)]
+		}
+		var read int
+		read, err = r.Read(dst)
+		buf.write = buf.write.Move(read)
+		n += int64(read)
+		switch {
+		case errors.Is(err, io.EOF):
+			return n, nil
+		case err != nil:
+			return n, err
+		case read > 0:
+			empty = 0
+		default:
+			if empty++; empty >= maxEmptyReads {
+				return n, io.ErrNoProgress
+			}
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package gobuffer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func newByteBuffer(s string) *ByteBuffer {
+	buf := NewByteBufferWithSize(4, 1)
+	for _, b := range []byte(s) {
+		buf.Write(b)
+	}
+	return buf
+}
+
+func TestByteBufferPeek(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		exp  string
+		err  error
+	}{
+		{"zero", 0, "", nil},
+		{"within row", 3, "hel", nil},
+		{"spanning rows", 7, "hello, ", nil},
+		{"all", 10, "hello, you", nil},
+		{"beyond buffered", 12, "hello, you", io.EOF},
+		{"negative", -1, "", bufio.ErrNegativeCount},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := newByteBuffer("hello, you")
+			got, err := buf.Peek(test.n)
+			if !errors.Is(err, test.err) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", test.err, err)
+			}
+			if string(got) != test.exp {
+				t.Errorf("unexpected peek:\nexp=%q\ngot=%q", test.exp, got)
+			}
+			if n := buf.Buffered(); n != 10 {
+				t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 10, n)
+			}
+		})
+	}
+}
+
+func TestByteBufferDiscard(t *testing.T) {
+	buf := newByteBuffer("abcdef")
+	n, err := buf.Discard(5)
+	if n != 5 || err != nil {
+		t.Errorf("unexpected discard:\nexp=%d/%v\ngot=%d/%v", 5, nil, n, err)
+	}
+	n, err = buf.Discard(5)
+	if n != 1 || !errors.Is(err, io.EOF) {
+		t.Errorf("unexpected discard:\nexp=%d/%v\ngot=%d/%v", 1, io.EOF, n, err)
+	}
+	if _, err = buf.Discard(-1); !errors.Is(err, bufio.ErrNegativeCount) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", bufio.ErrNegativeCount, err)
+	}
+}
+
+func TestByteBufferRead(t *testing.T) {
+	buf := newByteBuffer(`{"a":1}{"a":2}`)
+	dec := json.NewDecoder(buf)
+	var got []int
+	for {
+		var v struct{ A int }
+		if err := dec.Decode(&v); err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", io.EOF, err)
+			}
+			break
+		}
+		got = append(got, v.A)
+	}
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("unexpected values:\nexp=%v\ngot=%v", []int{1, 2}, got)
+	}
+	if n, err := buf.Read(nil); n != 0 || err != nil {
+		t.Errorf("unexpected read:\nexp=%d/%v\ngot=%d/%v", 0, nil, n, err)
+	}
+}
+
+func TestByteBufferReadSlice(t *testing.T) {
+	buf := newByteBuffer("ab\ncdefg\nhi")
+	for _, exp := range []string{"ab\n", "cdefg\n"} {
+		line, err := buf.ReadSlice('\n')
+		if err != nil || string(line) != exp {
+			t.Errorf("unexpected read slice:\nexp=%q\ngot=%q (%v)", exp, line, err)
+		}
+	}
+	line, err := buf.ReadSlice('\n')
+	if !errors.Is(err, io.EOF) || string(line) != "hi" {
+		t.Errorf("unexpected read slice:\nexp=%q/%v\ngot=%q/%v", "hi", io.EOF, line, err)
+	}
+	if n := buf.Buffered(); n != 0 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 0, n)
+	}
+}
+
+func TestByteBufferSeek(t *testing.T) {
+	buf := newByteBuffer("0123456789")
+	_, _ = buf.Discard(5)
+	buf.Commit()
+	tests := []struct {
+		name   string
+		offset int64
+		whence int
+		exp    int64
+		err    error
+	}{
+		{"start", 6, io.SeekStart, 6, nil},
+		{"current backward", -2, io.SeekCurrent, 4, nil},
+		{"end", -1, io.SeekEnd, 9, nil},
+		{"end position", 0, io.SeekEnd, 10, nil},
+		{"retained start", 4, io.SeekStart, 4, nil},
+		{"before retained", 3, io.SeekStart, 0, IllegalSeekError},
+		{"after end", 1, io.SeekEnd, 0, IllegalSeekError},
+		{"illegal whence", 0, 42, 0, IllegalSeekError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := buf.Seek(6, io.SeekStart); err != nil {
+				t.Fatalf("unexpected seek error: %v", err)
+			}
+			pos, err := buf.Seek(test.offset, test.whence)
+			if !errors.Is(err, test.err) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", test.err, err)
+			}
+			if err != nil {
+				test.exp = 6
+			} else if pos != test.exp {
+				t.Errorf("unexpected position:\nexp=%d\ngot=%d", test.exp, pos)
+			}
+			if n := buf.TotalConsumed(); n != uint64(test.exp) {
+				t.Errorf("unexpected read position:\nexp=%d\ngot=%d", test.exp, n)
+			}
+		})
+	}
+}
+
+func TestAsWriter(t *testing.T) {
+	buf := NewByteBufferWithSize(4, 1)
+	w := AsWriter(buf.Buffer)
+	if _, err := fmt.Fprintf(w, "%s=%d;", "answer", 42); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if _, err := io.Copy(w, strings.NewReader("more")); err != nil {
+		t.Fatalf("unexpected copy error: %v", err)
+	}
+	if got, _ := io.ReadAll(buf); string(got) != "answer=42;more" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "answer=42;more", got)
+	}
+	buf.Freeze()
+	if _, err := w.Write([]byte("x")); !errors.Is(err, FrozenError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", FrozenError, err)
+	}
+}
+
+func TestByteBufferReadByte(t *testing.T) {
+	buf := NewByteBufferWithSize(2, 1)
+	for _, b := range binary.AppendUvarint([]byte{0x01}, 300) {
+		buf.Write(b)
+	}
+	if err := buf.UnreadByte(); !errors.Is(err, bufio.ErrInvalidUnreadByte) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", bufio.ErrInvalidUnreadByte, err)
+	}
+	if c, err := buf.ReadByte(); c != 0x01 || err != nil {
+		t.Errorf("unexpected read:\nexp=%d/%v\ngot=%d/%v", 0x01, nil, c, err)
+	}
+	if v, err := binary.ReadUvarint(buf); v != 300 || err != nil {
+		t.Errorf("unexpected uvarint:\nexp=%d/%v\ngot=%d/%v", 300, nil, v, err)
+	}
+	if _, err := buf.ReadByte(); !errors.Is(err, io.EOF) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", io.EOF, err)
+	}
+	// Any retained byte may be unread
+	for range 3 {
+		if err := buf.UnreadByte(); err != nil {
+			t.Errorf("unexpected unread error: %v", err)
+		}
+	}
+	if c, _ := buf.ReadByte(); c != 0x01 {
+		t.Errorf("unexpected read:\nexp=%d\ngot=%d", 0x01, c)
+	}
+}
+
+func TestByteBufferReadFrom(t *testing.T) {
+	buf := NewByteBufferWithSize(4, 1)
+	buf.Write('>')
+	n, err := buf.ReadFrom(iotest.OneByteReader(strings.NewReader("abcdefghij")))
+	if n != 10 || err != nil {
+		t.Errorf("unexpected read from:\nexp=%d/%v\ngot=%d/%v", 10, nil, n, err)
+	}
+	n, err = io.Copy(AsWriter(buf.Buffer), iotest.DataErrReader(strings.NewReader("kl")))
+	if n != 2 || err != nil {
+		t.Errorf("unexpected copy:\nexp=%d/%v\ngot=%d/%v", 2, nil, n, err)
+	}
+	if _, err = buf.ReadFrom(iotest.ErrReader(iotest.ErrTimeout)); !errors.Is(err, iotest.ErrTimeout) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", iotest.ErrTimeout, err)
+	}
+	if got, _ := io.ReadAll(buf); string(got) != ">abcdefghijkl" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", ">abcdefghijkl", got)
+	}
+}
+
+// shortWriter is an io.Writer accepting at most max bytes.
+type shortWriter struct {
+	w   bytes.Buffer
+	max int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	n, _ := w.w.Write(p[:min(len(p), w.max-w.w.Len())])
+	return n, nil
+}
+
+func TestByteBufferWriteTo(t *testing.T) {
+	buf := newByteBuffer("abcdefghij")
+	w := &shortWriter{max: 6}
+	n, err := buf.WriteTo(w)
+	if n != 6 || !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("unexpected write to:\nexp=%d/%v\ngot=%d/%v", 6, io.ErrShortWrite, n, err)
+	}
+	var rest bytes.Buffer
+	n, err = io.Copy(&rest, buf)
+	if n != 4 || err != nil {
+		t.Errorf("unexpected copy:\nexp=%d/%v\ngot=%d/%v", 4, nil, n, err)
+	}
+	if got := w.w.String() + "|" + rest.String(); got != "abcdef|ghij" {
+		t.Errorf("unexpected written bytes:\nexp=%s\ngot=%s", "abcdef|ghij", got)
+	}
+}
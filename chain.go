@@ -0,0 +1,75 @@
+package gobuffer
+
+// chainView is a View reading the elements of several buffers as one stream.
+type chainView[T any] struct {
+	bufs   []*Buffer[T]
+	cur    int     // cur holds the index of the buffer currently read.
+	starts []State // starts holds the state of each buffer when the chain started reading it.
+}
+
+// NewChain creates a View reading the unconsumed elements of the provided buffers as one stream. The elements of a
+// buffer are read (and consumed in the buffer) before the elements of the next buffer. The chain moves on to the
+// next buffer when all elements in the current buffer have been consumed. Hence, elements written to the current
+// buffer while reading the chain are read before the elements of the next buffer.
+//
+// States created by the chain may be used to roll back the chain across buffer boundaries. A rollback fails (see
+// Buffer.Rollback) if the rollback position has been removed by a commit of the buffer.
+func NewChain[T any](bufs ...*Buffer[T]) View[T] {
+	c := &chainView[T]{
+		bufs:   bufs,
+		starts: make([]State, len(bufs)),
+	}
+	if len(bufs) > 0 {
+		c.starts[0] = bufs[0].State()
+	}
+	return c
+}
+
+func (c *chainView[T]) Next() (element T, ok bool) {
+	for c.cur < len(c.bufs) {
+		if element, ok = c.bufs[c.cur].Next(); ok || c.cur == len(c.bufs)-1 {
+			return
+		}
+		c.cur++
+		c.starts[c.cur] = c.bufs[c.cur].State()
+	}
+	return
+}
+
+func (c *chainView[T]) Consume() {
+	if _, ok := c.Next(); ok {
+		c.bufs[c.cur].Consume()
+	}
+}
+
+// State returns a chain state. The state holds the read position in the current buffer and the index of the
+// current buffer (as a write position without rows).
+func (c *chainView[T]) State() State {
+	if len(c.bufs) == 0 {
+		return newState(position{}, position{})
+	}
+	return newState(c.bufs[c.cur].State().read, position{Col: c.cur})
+}
+
+// Rollback resets the chain read state to the provided state. The buffers read after the state was created are
+// rolled back to their state when the chain started reading them. If the state wasn't created by the chain then
+// an IllegalStateError is returned.
+func (c *chainView[T]) Rollback(state State) error {
+	if !state.init {
+		return ZeroStateError
+	}
+	index := state.write.Col
+	if state.write.rowSize != 0 || index > c.cur || len(c.bufs) == 0 {
+		return IllegalStateError
+	}
+	if err := c.bufs[index].Rollback(newState(state.read, c.bufs[index].write)); err != nil {
+		return err
+	}
+	for i := index + 1; i <= c.cur; i++ {
+		if err := c.bufs[i].Rollback(c.starts[i]); err != nil {
+			return err
+		}
+	}
+	c.cur = index
+	return nil
+}
@@ -0,0 +1,91 @@
+package gobuffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewChain(t *testing.T) {
+	header := NewWithSize[rune](2, 1)
+	body := NewWithSize[rune](2, 1)
+	empty := New[rune]()
+	for _, r := range "GET /" {
+		header.Write(r)
+	}
+	for _, r := range "body" {
+		body.Write(r)
+	}
+	header.Consume()
+	chain := NewChain(header, empty, body)
+	r, _ := chain.Next()
+	if r != 'E' {
+		t.Errorf("unexpected next:\nexp=%c\ngot=%c", 'E', r)
+	}
+	chain.Consume()
+	state := chain.State()
+	if got := readView(chain); got != "T /body" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "T /body", got)
+	}
+	if err := chain.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	if got := readView(chain); got != "T /body" {
+		t.Errorf("unexpected content after rollback:\nexp=%s\ngot=%s", "T /body", got)
+	}
+	// Elements written to the last buffer are read by the chain
+	body.Write('!')
+	if got := readView(chain); got != "!" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "!", got)
+	}
+}
+
+func TestNewChain_RollbackErrors(t *testing.T) {
+	first := NewWithSize[int](1, 1)
+	second := NewWithSize[int](1, 1)
+	for i := 0; i < 3; i++ {
+		first.Write(i)
+		second.Write(i)
+	}
+	chain := NewChain(first, second)
+	start := chain.State()
+	for i := 0; i < 4; i++ {
+		chain.Consume()
+	}
+	later := chain.State()
+	if err := chain.Rollback(start); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	tests := []struct {
+		name  string
+		state State
+		err   error
+	}{
+		{"zero state", State{}, ZeroStateError},
+		{"later buffer", later, IllegalStateError},
+		{"buffer state", first.State(), IllegalStateError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := chain.Rollback(test.state); !errors.Is(err, test.err) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", test.err, err)
+			}
+		})
+	}
+	first.Commit()
+	chain.Consume()
+	first.Commit()
+	if err := chain.Rollback(start); !errors.Is(err, IllegalStateError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", IllegalStateError, err)
+	}
+}
+
+func TestNewChain_Empty(t *testing.T) {
+	chain := NewChain[int]()
+	if _, ok := chain.Next(); ok {
+		t.Errorf("unexpected next element")
+	}
+	chain.Consume()
+	if err := chain.Rollback(chain.State()); !errors.Is(err, IllegalStateError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", IllegalStateError, err)
+	}
+}
@@ -0,0 +1,72 @@
+package gobuffer
+
+import "io"
+
+// ChanSource is a Source receiving elements from a channel. When the channel is closed (and drained) the source
+// is exhausted.
+type ChanSource[T any] struct {
+	ch       <-chan T
+	blocking bool
+}
+
+// NewChanSource creates a new ChanSource receiving elements from the provided channel. By default, Read blocks
+// until an element is received (see ChanSource.NonBlocking).
+func NewChanSource[T any](ch <-chan T) *ChanSource[T] {
+	return &ChanSource[T]{ch: ch, blocking: true}
+}
+
+// NonBlocking makes the ChanSource return NotReadyError instead of blocking when no element is available in the
+// channel. Hence, Buffer.Next returns false when the Buffer runs out of elements instead of waiting for the
+// producer. The ChanSource is returned.
+//
+// A non-blocking ChanSource should not be prefetched (see Buffer.WithPrefetch) as the prefetching stops at the
+// first error (including NotReadyError).
+func (s *ChanSource[T]) NonBlocking() *ChanSource[T] {
+	s.blocking = false
+	return s
+}
+
+// Read receives up to len(p) elements from the channel into p. Read only waits for the first element (unless
+// non-blocking). Following elements are only received if immediately available. When the channel is closed
+// io.EOF is returned.
+func (s *ChanSource[T]) Read(p []T) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if !s.blocking {
+		select {
+		case element, ok := <-s.ch:
+			if !ok {
+				return 0, io.EOF
+			}
+			p[0] = element
+		default:
+			return 0, NotReadyError
+		}
+	} else {
+		element, ok := <-s.ch
+		if !ok {
+			return 0, io.EOF
+		}
+		p[0] = element
+	}
+	for n = 1; n < len(p); n++ {
+		select {
+		case element, ok := <-s.ch:
+			if !ok {
+				return n, io.EOF
+			}
+			p[n] = element
+		default:
+			return n, nil
+		}
+	}
+	return n, nil
+}
+
+// NewFromChannel creates a new Buffer receiving elements from the provided channel (see NewChanSource and
+// NewFromSource). Buffer.Next blocks until an element is received when the Buffer runs out of elements. Use
+// NewFromSource(NewChanSource(ch).NonBlocking()) for a Buffer not waiting for the producer.
+func NewFromChannel[T any](ch <-chan T) *Buffer[T] {
+	return NewFromSource[T](NewChanSource(ch))
+}
@@ -0,0 +1,53 @@
+package gobuffer
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNewFromChannel(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := range 5 {
+			ch <- i
+		}
+	}()
+	buf := NewFromChannel(ch)
+	var got []int
+	for n, ok := buf.Pop(); ok; n, ok = buf.Pop() {
+		got = append(got, n)
+	}
+	if !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("unexpected elements:\nexp=%v\ngot=%v", []int{0, 1, 2, 3, 4}, got)
+	}
+	if err := buf.Err(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestChanSource_NonBlocking(t *testing.T) {
+	ch := make(chan int, 4)
+	buf := NewFromSource[int](NewChanSource(ch).NonBlocking())
+	if _, ok := buf.Next(); ok {
+		t.Errorf("unexpected next element")
+	}
+	if err := buf.Err(); !errors.Is(err, NotReadyError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", NotReadyError, err)
+	}
+	// The source is pulled again when elements are available
+	ch <- 1
+	ch <- 2
+	close(ch)
+	var got []int
+	for n, ok := buf.Pop(); ok; n, ok = buf.Pop() {
+		got = append(got, n)
+	}
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("unexpected elements:\nexp=%v\ngot=%v", []int{1, 2}, got)
+	}
+	if err := buf.Err(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package gobuffer
+
+import "errors"
+
+var UnknownCheckpointError = errors.New("unknown checkpoint")
+
+// Checkpoint creates a named checkpoint holding the current read state of the Buffer. A checkpoint is a savepoint
+// (see Buffer.Savepoint) kept by the Buffer under the provided name. Hence, a rollback to a checkpoint isn't
+// prevented by Buffer.Commit as long as the checkpoint isn't released (see Buffer.ReleaseCheckpoint). If a
+// checkpoint with the same name exists then it is replaced.
+func (b *Buffer[T]) Checkpoint(name string) {
+	if s, ok := b.checkpoints[name]; ok {
+		s.Release()
+	}
+	if b.checkpoints == nil {
+		b.checkpoints = make(map[string]*Savepoint[T])
+	}
+	b.checkpoints[name] = b.Savepoint()
+}
+
+// RollbackTo resets the Buffer read state to the named checkpoint (see Buffer.Checkpoint). The checkpoint is still
+// live after the rollback. If no checkpoint with the name exists then an UnknownCheckpointError is returned.
+func (b *Buffer[T]) RollbackTo(name string) error {
+	s, ok := b.checkpoints[name]
+	if !ok {
+		return b.misused("RollbackTo", UnknownCheckpointError, true)
+	}
+	return s.Rollback()
+}
+
+// ReleaseCheckpoint releases the named checkpoint (see Savepoint.Release). Releasing a checkpoint not existing has
+// no effect.
+func (b *Buffer[T]) ReleaseCheckpoint(name string) {
+	if s, ok := b.checkpoints[name]; ok {
+		delete(b.checkpoints, name)
+		s.Release()
+	}
+}
@@ -0,0 +1,49 @@
+package gobuffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBufferCheckpoint(t *testing.T) {
+	buf := NewWithSize[int](2, 1)
+	for i := 0; i < 10; i++ {
+		buf.Write(i)
+	}
+	buf.Consume()
+	buf.Checkpoint("statement")
+	buf.Consume()
+	buf.Checkpoint("expression")
+	buf.Consume()
+	buf.Checkpoint("expression")
+	for i := 0; i < 4; i++ {
+		buf.Consume()
+	}
+	// Commit keeps the rows of the live checkpoints
+	buf.Commit()
+	if err := buf.RollbackTo("expression"); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	if next, _ := buf.Next(); next != 3 {
+		t.Errorf("unexpected next:\nexp=%d\ngot=%d", 3, next)
+	}
+	if err := buf.RollbackTo("statement"); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	if next, _ := buf.Next(); next != 1 {
+		t.Errorf("unexpected next:\nexp=%d\ngot=%d", 1, next)
+	}
+	for i := 0; i < 7; i++ {
+		buf.Consume()
+	}
+	// Releasing the checkpoints trims the rows up to the read position
+	buf.ReleaseCheckpoint("statement")
+	buf.ReleaseCheckpoint("expression")
+	buf.ReleaseCheckpoint("expression")
+	if rows := buf.RetentionReport().Rows; rows != 1 {
+		t.Errorf("unexpected rows:\nexp=%d\ngot=%d", 1, rows)
+	}
+	if err := buf.RollbackTo("statement"); !errors.Is(err, UnknownCheckpointError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", UnknownCheckpointError, err)
+	}
+}
@@ -0,0 +1,31 @@
+package gobuffer
+
+import "slices"
+
+// Clone returns a deep copy of the Buffer. The copy holds copies of the rows of the Buffer and has the same read
+// and write positions. Hence, states created by the Buffer (see Buffer.State) are valid for the copy as well.
+//
+// Only the elements, the positions, the retained history (see Buffer.WithHistory), the element limit (see
+// Buffer.WithMaxElements), the misuse policy and the frozen state are copied. The source, hooks, observers,
+// watchers, statistics, savepoints, checkpoints and row storage of the Buffer are not carried over to the copy.
+func (b *Buffer[T]) Clone() *Buffer[T] {
+	rows := make([][]T, len(b.buffers), cap(b.buffers))
+	for i, row := range b.buffers {
+		rows[i] = slices.Clone(row)
+	}
+	return &Buffer[T]{
+		rowSize:      b.rowSize,
+		startRow:     b.startRow,
+		buffers:      rows,
+		read:         b.read,
+		write:        b.write,
+		pinned:       b.pinned,
+		released:     b.released,
+		frozen:       b.frozen,
+		misusePolicy: b.misusePolicy,
+		history:      b.history,
+		maxElements:  b.maxElements,
+		seqOffset:    b.seqOffset,
+		seqMarks:     slices.Clone(b.seqMarks),
+	}
+}
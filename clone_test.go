@@ -0,0 +1,27 @@
+package gobuffer
+
+import "testing"
+
+func TestBufferClone(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abcde" {
+		buf.Write(r)
+	}
+	buf.Pop()
+	state := buf.State()
+	clone := buf.Clone()
+	// Modifying the clone doesn't affect the Buffer
+	clone.Apply(func(r *rune) { *r -= 'a' - 'A' })
+	clone.Write('f')
+	clone.Pop()
+	if got := readAll(buf); got != "bcde" {
+		t.Errorf("unexpected buffer content:\nexp=%s\ngot=%s", "bcde", got)
+	}
+	// States of the Buffer are valid for the clone
+	if err := clone.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	if got := readAll(clone); got != "BCDEf" {
+		t.Errorf("unexpected clone content:\nexp=%s\ngot=%s", "BCDEf", got)
+	}
+}
@@ -0,0 +1,91 @@
+package gobuffer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// Compressor compresses the rows of a Buffer snapshot (see CompressedCodec).
+type Compressor interface {
+	// Compress returns the compressed form of the provided data.
+	Compress(data []byte) ([]byte, error)
+	// Decompress returns the data compressed by Compress. The decompressed data should be bounded (e.g. to the
+	// maximum size of a snapshot frame) to protect against malformed snapshots.
+	Decompress(data []byte) ([]byte, error)
+}
+
+// GzipCompressor is a Compressor using gzip compression (see compress/gzip). The zero value uses
+// gzip.DefaultCompression. Use NewGzipCompressor to select another compression level.
+type GzipCompressor struct {
+	level   int
+	leveled bool // leveled is true if the compression level is set (see NewGzipCompressor).
+}
+
+// NewGzipCompressor creates a new GzipCompressor using the specified compression level (e.g. gzip.NoCompression
+// or gzip.BestSpeed). If the level isn't a valid gzip compression level then a panic is raised.
+func NewGzipCompressor(level int) GzipCompressor {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		panic(argumentError{"illegal gzip compression level", level})
+	}
+	return GzipCompressor{level: level, leveled: true}
+}
+
+func (c GzipCompressor) Compress(data []byte) ([]byte, error) {
+	level := gzip.DefaultCompression
+	if c.leveled {
+		level = c.level
+	}
+	var compressed bytes.Buffer
+	w, err := gzip.NewWriterLevel(&compressed, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return compressed.Bytes(), nil
+}
+
+// maxDecompressedSize is the maximum number of bytes returned by GzipCompressor.Decompress (the maximum size of a
+// snapshot frame). It is a variable to let tests use a smaller limit.
+var maxDecompressedSize = snapshotMaxFrameSize
+
+func (c GzipCompressor) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	// The decompressed size is limited. Hence, a small compressed row can't expand without limit.
+	decompressed, err := io.ReadAll(io.LimitReader(r, int64(maxDecompressedSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(decompressed) > maxDecompressedSize {
+		return nil, SnapshotFormatError
+	}
+	return decompressed, nil
+}
+
+// CompressedCodec is a Codec making Buffer snapshots compress the encoded elements of each row (see
+// Buffer.SaveTo and RestoreFrom). The elements are encoded using the embedded codec. A compressed snapshot must
+// be restored using a CompressedCodec with a compatible Compressor.
+type CompressedCodec[T any] struct {
+	Codec[T]
+	Compressor Compressor
+}
+
+func (c CompressedCodec[T]) compressor() Compressor {
+	return c.Compressor
+}
+
+// compressorOf returns the row compressor of the codec (nil if the codec doesn't compress rows).
+func compressorOf[T any](codec Codec[T]) Compressor {
+	if c, ok := codec.(interface{ compressor() Compressor }); ok {
+		return c.compressor()
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package gobuffer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGzipCompressor(t *testing.T) {
+	data := []byte(strings.Repeat("hello world ", 100))
+	tests := []struct {
+		name       string
+		compressor GzipCompressor
+	}{
+		{"default", GzipCompressor{}},
+		{"no compression", NewGzipCompressor(gzip.NoCompression)},
+		{"best speed", NewGzipCompressor(gzip.BestSpeed)},
+		{"best compression", NewGzipCompressor(gzip.BestCompression)},
+		{"huffman only", NewGzipCompressor(gzip.HuffmanOnly)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			compressed, err := test.compressor.Compress(data)
+			if err != nil {
+				t.Fatalf("unexpected compress error: %v", err)
+			}
+			got, err := test.compressor.Decompress(compressed)
+			if err != nil {
+				t.Fatalf("unexpected decompress error: %v", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("unexpected decompressed data:\nexp=%s\ngot=%s", data, got)
+			}
+		})
+	}
+}
+
+func TestGzipCompressor_Level(t *testing.T) {
+	data := []byte(strings.Repeat("hello world ", 100))
+	stored, _ := NewGzipCompressor(gzip.NoCompression).Compress(data)
+	if !bytes.Contains(stored, data) {
+		t.Errorf("expected data to be stored uncompressed")
+	}
+	compressed, _ := GzipCompressor{}.Compress(data)
+	if len(compressed) >= len(stored) {
+		t.Errorf("unexpected compressed size:\nexp=<%d\ngot=%d", len(stored), len(compressed))
+	}
+}
+
+func TestGzipCompressor_IllegalLevelPanic(t *testing.T) {
+	defer func() { _ = recover() }()
+	NewGzipCompressor(gzip.BestCompression + 1)
+	t.Errorf("expected NewGzipCompressor to panic")
+}
+
+func TestGzipCompressor_CorruptInput(t *testing.T) {
+	compressed, _ := GzipCompressor{}.Compress([]byte("hello world"))
+	corrupt := bytes.Clone(compressed)
+	corrupt[len(corrupt)-5] ^= 0xff
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not gzip", []byte("hello world")},
+		{"truncated", compressed[:len(compressed)-4]},
+		{"corrupt", corrupt},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := (GzipCompressor{}).Decompress(test.data); err == nil {
+				t.Errorf("expected decompress error")
+			}
+		})
+	}
+}
+
+func TestGzipCompressor_Bomb(t *testing.T) {
+	defer func(size int) { maxDecompressedSize = size }(maxDecompressedSize)
+	maxDecompressedSize = 1 << 16
+	// A small compressed row expanding beyond the maximum size
+	bomb, _ := NewGzipCompressor(gzip.BestCompression).Compress(make([]byte, maxDecompressedSize+1))
+	if _, err := (GzipCompressor{}).Decompress(bomb); !errors.Is(err, SnapshotFormatError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", SnapshotFormatError, err)
+	}
+}
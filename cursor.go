@@ -0,0 +1,147 @@
+package gobuffer
+
+import "slices"
+
+// Cursor is an independent read position over the elements of a Buffer (see Buffer.NewCursor). A Cursor has the
+// same lookahead (Next and Consume) and rollback (State and Rollback) as the Buffer itself, while the Buffer rows
+// are shared with the Buffer and all other cursors. Reading from a Cursor doesn't affect the read position of the
+// Buffer or any other Cursor.
+//
+// Buffer.Commit doesn't remove the rows at or after the committed position of any open Cursor (see
+// Cursor.Commit). Hence, a slow Cursor holds on to the rows it has not yet committed.
+type Cursor[T any] struct {
+	buf    *Buffer[T]
+	read   position // read holds the position of the next element to read.
+	commit position // commit holds the position before which the Cursor doesn't need any rows.
+}
+
+var _ View[any] = (*Cursor[any])(nil)
+
+// NewCursor creates a new Cursor starting at the current read position of the Buffer. The Cursor must be closed
+// when no longer used (see Cursor.Close).
+func (b *Buffer[T]) NewCursor() *Cursor[T] {
+	c := &Cursor[T]{buf: b, read: b.read, commit: b.read}
+	b.cursors = append(b.cursors, c)
+	return c
+}
+
+// Next returns the next element from the Cursor. If there are no unread elements then false is returned. Like
+// Buffer.Next, elements are pulled from the source of the Buffer (if any) when needed.
+func (c *Cursor[T]) Next() (element T, ok bool) {
+	b := c.buf
+	for c.Buffered() == 0 {
+		if b.source == nil || !b.pull() {
+			return
+		}
+	}
+	row, col := b.bufferPos(c.read)
+	return b.buffers[row][col], true
+}
+
+// Consume consumes the next element (returned by Cursor.Next). Consuming from an empty Cursor has no effect.
+func (c *Cursor[T]) Consume() {
+	if c.Buffered() > 0 {
+		c.read = c.read.Move(1)
+	}
+}
+
+// Buffered returns the number of elements not yet consumed by the Cursor.
+func (c *Cursor[T]) Buffered() int {
+	return c.buf.write.AbsolutePos() - c.read.AbsolutePos()
+}
+
+// State returns a state that may be used to roll back the Cursor to the current read position.
+func (c *Cursor[T]) State() State {
+	return newState(c.read, c.buf.write)
+}
+
+// Rollback resets the read position of the Cursor to the provided state. States created by the Buffer (and other
+// cursors of the Buffer) may be used as well. If the provided state is the "zero state" then a ZeroStateError is
+// returned. If the state position is no longer retained by the Buffer (or wasn't created by the Buffer) then an
+// IllegalStateError is returned.
+func (c *Cursor[T]) Rollback(state State) error {
+	if !state.init {
+		return ZeroStateError
+	}
+	b := c.buf
+	if state.read.rowSize != b.rowSize || state.read.Row < b.startRow ||
+		state.read.AbsolutePos() > b.write.AbsolutePos() {
+		return IllegalStateError
+	}
+	c.read = state.read
+	c.commit = position{rowSize: b.rowSize, Row: min(c.commit.Row, state.read.Row)}
+	return nil
+}
+
+// Commit tells the Buffer that the Cursor no longer needs the elements before its read position. The rows only
+// held for the Cursor are removed by the next Buffer.Commit. States created by the Cursor before the commit may
+// no longer be valid after that.
+func (c *Cursor[T]) Commit() {
+	c.commit = c.read
+}
+
+// Close closes the Cursor. The Buffer no longer retains any rows for the Cursor. The Cursor must not be used
+// after Close. Closing a Cursor more than once has no effect.
+func (c *Cursor[T]) Close() {
+	b := c.buf
+	if i := slices.Index(b.cursors, c); i >= 0 {
+		b.cursors = slices.Delete(b.cursors, i, i+1)
+	}
+}
+
+// clampCursors moves the read and commit positions of all cursors beyond the write position back to the write
+// position. It is called when the write position of the Buffer moves backward (e.g. by Buffer.Truncate).
+func (b *Buffer[T]) clampCursors() {
+	for _, c := range b.cursors {
+		if c.read.AbsolutePos() > b.write.AbsolutePos() {
+			c.read = b.write
+		}
+		if c.commit.AbsolutePos() > b.write.AbsolutePos() {
+			c.commit = b.write
+		}
+	}
+}
+
+// Fork creates a branch of the Buffer. The branch is a Cursor starting at the current read position of the Buffer
+// (see Buffer.NewCursor). Reading from the branch doesn't affect the Buffer. When the branch is done its read
+// position is either adopted by the Buffer (see Buffer.Join) or discarded (see Cursor.Close).
+func (b *Buffer[T]) Fork() *Cursor[T] {
+	return b.NewCursor()
+}
+
+// Join moves the read position of the Buffer to the read position of the branch (see Buffer.Fork) and closes the
+// branch. If the branch has read ahead of the Buffer then the elements in between are consumed by the Buffer (like
+// Buffer.ConsumeN). If the branch isn't a Cursor of the Buffer then an IllegalStateError is returned.
+func (b *Buffer[T]) Join(branch *Cursor[T]) error {
+	if branch.buf != b {
+		return b.misused("Join", IllegalStateError, true)
+	}
+	if n := branch.read.AbsolutePos() - b.read.AbsolutePos(); n >= 0 {
+		b.consume(n)
+	} else if err := b.Rollback(newState(branch.read, b.write)); err != nil {
+		return err
+	}
+	branch.Close()
+	return nil
+}
+
+// Fork creates a branch of the Cursor. The branch is a new Cursor starting at the read position of the Cursor
+// (see Buffer.Fork). The branch is adopted by Cursor.Join or discarded by Cursor.Close.
+func (c *Cursor[T]) Fork() *Cursor[T] {
+	branch := c.buf.NewCursor()
+	branch.read, branch.commit = c.read, c.read
+	return branch
+}
+
+// Join moves the read position of the Cursor to the read position of the branch (see Cursor.Fork) and closes the
+// branch. If the branch isn't a Cursor of the same Buffer then an IllegalStateError is returned.
+func (c *Cursor[T]) Join(branch *Cursor[T]) error {
+	if branch.buf != c.buf {
+		return IllegalStateError
+	}
+	if err := c.Rollback(newState(branch.read, c.buf.write)); err != nil {
+		return err
+	}
+	branch.Close()
+	return nil
+}
@@ -0,0 +1,115 @@
+package gobuffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func readCursor(c *Cursor[rune], n int) string {
+	var s []rune
+	for r, ok := c.Next(); ok && len(s) < n; r, ok = c.Next() {
+		s = append(s, r)
+		c.Consume()
+	}
+	return string(s)
+}
+
+func TestBufferNewCursor(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abcdefgh" {
+		buf.Write(r)
+	}
+	fast, slow := buf.NewCursor(), buf.NewCursor()
+	if got := readCursor(fast, 8); got != "abcdefgh" {
+		t.Errorf("unexpected fast cursor content:\nexp=%s\ngot=%s", "abcdefgh", got)
+	}
+	fast.Commit()
+	state := slow.State()
+	if got := readCursor(slow, 3); got != "abc" {
+		t.Errorf("unexpected slow cursor content:\nexp=%s\ngot=%s", "abc", got)
+	}
+	// The Buffer read position is not affected by the cursors
+	if got := readAll(buf); got != "abcdefgh" {
+		t.Errorf("unexpected buffer content:\nexp=%s\ngot=%s", "abcdefgh", got)
+	}
+	// The rows not committed by the slow cursor are retained
+	buf.Commit()
+	if err := slow.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	if got := readCursor(slow, 4); got != "abcd" {
+		t.Errorf("unexpected slow cursor content:\nexp=%s\ngot=%s", "abcd", got)
+	}
+	slow.Commit()
+	buf.Commit()
+	if err := slow.Rollback(state); !errors.Is(err, IllegalStateError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", IllegalStateError, err)
+	}
+	slow.Close()
+	fast.Close()
+	buf.Commit()
+	if buf.startRow != 4 {
+		t.Errorf("unexpected start row:\nexp=%d\ngot=%d", 4, buf.startRow)
+	}
+}
+
+func TestCursor_Truncate(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abcdef" {
+		buf.Write(r)
+	}
+	c := buf.NewCursor()
+	readCursor(c, 5)
+	c.Commit()
+	// Truncating behind the cursor moves the cursor to the new write position
+	buf.Truncate(1)
+	if n := c.Buffered(); n != 0 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 0, n)
+	}
+	if r, ok := c.Next(); ok {
+		t.Errorf("unexpected next element: %c", r)
+	}
+	buf.Write('x')
+	if r, _ := c.Next(); r != 'x' {
+		t.Errorf("unexpected next element:\nexp=%c\ngot=%c", 'x', r)
+	}
+	// Consuming from the back behind the cursor moves the cursor as well
+	buf.ConsumeBack()
+	if n := c.Buffered(); n != 0 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 0, n)
+	}
+}
+
+func TestBufferFork(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "x=1;y=2;" {
+		buf.Write(r)
+	}
+	// Explore an alternative and discard it
+	branch := buf.Fork()
+	readCursor(branch, 6)
+	branch.Close()
+	if r, _ := buf.Next(); r != 'x' {
+		t.Errorf("unexpected next element:\nexp=%c\ngot=%c", 'x', r)
+	}
+	// Explore an alternative with a nested branch and adopt it
+	branch = buf.Fork()
+	readCursor(branch, 2)
+	nested := branch.Fork()
+	readCursor(nested, 2)
+	if err := branch.Join(nested); err != nil {
+		t.Fatalf("unexpected join error: %v", err)
+	}
+	if err := buf.Join(branch); err != nil {
+		t.Fatalf("unexpected join error: %v", err)
+	}
+	if got := readAll(buf); got != "y=2;" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "y=2;", got)
+	}
+	if n := len(buf.cursors); n != 0 {
+		t.Errorf("unexpected open cursors:\nexp=%d\ngot=%d", 0, n)
+	}
+	if err := buf.Join(NewWithSize[rune](2, 1).Fork()); !errors.Is(err, IllegalStateError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", IllegalStateError, err)
+	}
+}
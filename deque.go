@@ -0,0 +1,91 @@
+package gobuffer
+
+import "errors"
+
+var NoFrontRoomError = errors.New("no room before the first element of the buffer")
+
+// NextBack returns the last unconsumed element in the Buffer (the element most recently written). If there are
+// no unconsumed elements in the Buffer then false is returned. Unlike Buffer.Next no elements are pulled from the
+// source of the Buffer (see NewFromSource).
+//
+// NextBack, Buffer.ConsumeBack and Buffer.WriteFront make the Buffer usable as a deque over the unconsumed
+// elements.
+func (b *Buffer[T]) NextBack() (element T, ok bool) {
+	if b.Buffered() == 0 {
+		return
+	}
+	row, col := b.bufferPos(b.write.Move(-1))
+	return b.buffers[row][col], true
+}
+
+// ConsumeBack removes the last unconsumed element in the Buffer (returned by Buffer.NextBack). That is, the
+// write position is moved one element backward (like Buffer.Truncate). The removed element is passed to the
+// commit finalizer (see Buffer.WithCommitFinalizer).
+//
+// Consuming from an empty Buffer is a misuse (see MisusePolicy) and has no effect. So is consuming from the back
+// of a frozen Buffer (see Buffer.Freeze).
+func (b *Buffer[T]) ConsumeBack() {
+	if b.Buffered() == 0 {
+		_ = b.misused("ConsumeBack", EmptyConsumeError, false)
+		return
+	}
+	if !b.checkWritable("ConsumeBack") {
+		return
+	}
+	b.unshare()
+	b.write = b.write.Move(-1)
+	b.discardedBack(1)
+	b.clampCursors()
+	row, col := b.bufferPos(b.write)
+	if b.commitFinalizer != nil {
+		b.commitFinalizer(b.buffers[row][col])
+	}
+	clear(b.buffers[row][col : col+1])
+	b.sample()
+}
+
+// WriteFront writes the element before the first unconsumed element in the Buffer. That is, the written element
+// is the next element returned by Buffer.Next. The element replaces the consumed element before the read
+// position. Hence, states created before the write (see Buffer.State) and the history of the Buffer (see
+// Buffer.WithHistory) see the written element instead of the consumed element. The replaced element is passed to
+// the commit finalizer (see Buffer.WithCommitFinalizer) and the consumed hook (see Buffer.OnConsumed) unless
+// already passed to it.
+//
+// If the element before the read position has been removed by Buffer.Commit (or was never written) then there is
+// no room before it and the write is a misuse with the error NoFrontRoomError (see MisusePolicy). So is writing
+// to a frozen Buffer (see Buffer.Freeze) and writing to a Buffer holding the maximum number of elements (see
+// Buffer.WithMaxElements). Such a write has no effect.
+func (b *Buffer[T]) WriteFront(element T) {
+	front := b.read.Move(-1)
+	if b.read.AbsolutePos() == 0 || front.Row < b.startRow {
+		_ = b.misused("WriteFront", NoFrontRoomError, false)
+		return
+	}
+	if !b.checkWritable("WriteFront") {
+		return
+	}
+	if b.maxElements > 0 && !b.checkRoom("WriteFront", 1) {
+		return
+	}
+	b.unshare()
+	row, col := b.bufferPos(front)
+	displaced := b.buffers[row][col]
+	if b.onConsumed != nil {
+		if front.AbsolutePos() < b.released {
+			// The displaced element has been passed to the hook while the written element is yet to be consumed
+			b.released = front.AbsolutePos()
+		} else {
+			b.onConsumed(displaced)
+		}
+	}
+	if b.commitFinalizer != nil {
+		b.commitFinalizer(displaced)
+	}
+	b.buffers[row][col] = element
+	b.writtenFront(front.AbsolutePos())
+	b.read = front
+	b.sample()
+	for _, w := range b.watchers {
+		w.fn(element)
+	}
+}
@@ -0,0 +1,96 @@
+package gobuffer
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBufferDeque(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abcdef" {
+		buf.Write(r)
+	}
+	for range 4 {
+		buf.Next()
+		buf.Consume()
+	}
+	if r, ok := buf.NextBack(); !ok || r != 'f' {
+		t.Errorf("unexpected back element:\nexp=%c, %v\ngot=%c, %v", 'f', true, r, ok)
+	}
+	buf.ConsumeBack()
+	// Writing to the front replaces the consumed elements
+	for _, r := range "321" {
+		buf.WriteFront(r)
+	}
+	buf.Write('g')
+	if got := readAll(buf); got != "123eg" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "123eg", got)
+	}
+	if _, ok := buf.NextBack(); ok {
+		t.Errorf("unexpected back element in empty buffer")
+	}
+	buf.ConsumeBack()
+	if err := buf.Misuse(); !errors.Is(err, EmptyConsumeError) {
+		t.Errorf("unexpected misuse:\nexp=%v\ngot=%v", EmptyConsumeError, err)
+	}
+}
+
+func TestBufferWriteFront_NoRoom(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	buf.Write('b')
+	buf.WriteFront('a')
+	if err := buf.Misuse(); !errors.Is(err, NoFrontRoomError) {
+		t.Errorf("unexpected misuse:\nexp=%v\ngot=%v", NoFrontRoomError, err)
+	}
+	if got := readAll(buf); got != "b" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "b", got)
+	}
+	// The rows removed by a commit are not added back
+	for _, r := range "cd" {
+		buf.Write(r)
+	}
+	buf.Pop()
+	buf.Commit()
+	buf.WriteFront('x')
+	if err := buf.Misuse(); !errors.Is(err, NoFrontRoomError) {
+		t.Errorf("unexpected misuse:\nexp=%v\ngot=%v", NoFrontRoomError, err)
+	}
+	if got := readAll(buf); got != "d" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "d", got)
+	}
+}
+
+func TestBufferWriteFront_Finalized(t *testing.T) {
+	var finalized, consumed []string
+	buf := NewWithSize[string](4, 1).
+		WithCommitFinalizer(func(s string) { finalized = append(finalized, s) }).
+		OnConsumed(func(s string) { consumed = append(consumed, s) })
+	for _, s := range []string{"a", "b", "c", "d"} {
+		buf.Write(s)
+	}
+	buf.Pop()
+	buf.Pop()
+	// The displaced element is finalized when replaced and the written element by the commit
+	buf.WriteFront("x")
+	for buf.Buffered() > 0 {
+		buf.Pop()
+	}
+	buf.Commit()
+	if exp := []string{"b", "a", "x", "c", "d"}; !slices.Equal(finalized, exp) {
+		t.Errorf("unexpected finalized elements:\nexp=%v\ngot=%v", exp, finalized)
+	}
+	if exp := []string{"a", "b", "x", "c", "d"}; !slices.Equal(consumed, exp) {
+		t.Errorf("unexpected consumed elements:\nexp=%v\ngot=%v", exp, consumed)
+	}
+	// Writing to the front is limited by the maximum number of elements
+	buf = NewWithSize[string](4, 1).WithMaxElements(2)
+	for _, s := range []string{"a", "b"} {
+		buf.Write(s)
+	}
+	buf.Pop()
+	buf.WriteFront("x")
+	if err := buf.Misuse(); !errors.Is(err, BufferFullError) {
+		t.Errorf("unexpected misuse:\nexp=%v\ngot=%v", BufferFullError, err)
+	}
+}
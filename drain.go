@@ -0,0 +1,57 @@
+package gobuffer
+
+import (
+	"context"
+	"time"
+)
+
+// DrainTo repeatedly calls fn with contiguous slices of unconsumed elements until all elements in the Buffer
+// are consumed. The elements passed to fn are consumed when fn returns without error. If fn returns an error
+// then the elements passed in that call are not consumed, the draining stops and the error is returned.
+//
+// The slices passed to fn reference the Buffer rows (no copying) and must not be retained after fn returns.
+func (b *Buffer[T]) DrainTo(fn func([]T) error) (err error) {
+	b.chunks(b.read, b.write, func(chunk []T) bool {
+		if err = fn(chunk); err != nil {
+			return false
+		}
+		b.consume(len(chunk))
+		return true
+	})
+	return
+}
+
+// DrainRate consumes the unconsumed elements in the Buffer at a rate of at most perSecond elements per second,
+// calling fn for each element. The first element is passed to fn immediately and subsequent elements are paced
+// using an internal ticker.
+//
+// An element is consumed when fn returns without error. If fn returns an error then the element is not consumed,
+// the draining stops and the error is returned. If the context is done before all elements are drained then the
+// context error is returned. If perSecond is <= 0 then a panic is raised.
+func (b *Buffer[T]) DrainRate(ctx context.Context, perSecond int, fn func(T) error) error {
+	if perSecond <= 0 {
+		panic(argumentError{"illegal non-positive rate", perSecond})
+	}
+	// A rate beyond one element per nanosecond is paced by the shortest possible interval
+	ticker := time.NewTicker(max(time.Second/time.Duration(perSecond), time.Nanosecond))
+	defer ticker.Stop()
+	for first := true; ; first = false {
+		element, ok := b.Next()
+		if !ok {
+			return nil
+		}
+		if !first {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(element); err != nil {
+			return err
+		}
+		b.consume(1)
+	}
+}
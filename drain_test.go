@@ -0,0 +1,113 @@
+package gobuffer
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBufferDrainTo(t *testing.T) {
+	buf := NewWithSize[int](3, 1)
+	for i := 0; i < 7; i++ {
+		buf.Write(i)
+	}
+	buf.Consume()
+	var got []int
+	err := buf.DrainTo(func(chunk []int) error {
+		got = append(got, chunk...)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	exp := []int{1, 2, 3, 4, 5, 6}
+	if !slices.Equal(got, exp) {
+		t.Errorf("unexpected drained elements:\nexp=%v\ngot=%v", exp, got)
+	}
+	if n := buf.Buffered(); n != 0 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 0, n)
+	}
+}
+
+func TestBufferDrainTo_Error(t *testing.T) {
+	buf := NewWithSize[int](3, 1)
+	for i := 0; i < 7; i++ {
+		buf.Write(i)
+	}
+	sinkErr := errors.New("sink full")
+	calls := 0
+	err := buf.DrainTo(func(chunk []int) error {
+		calls++
+		if calls == 2 {
+			return sinkErr
+		}
+		return nil
+	})
+	if !errors.Is(err, sinkErr) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", sinkErr, err)
+	}
+	// Only the first chunk should have been consumed
+	r, ok := buf.Next()
+	if !ok || r != 3 {
+		t.Errorf("unexpected next:\nexp=%d\ngot=%d (%v)", 3, r, ok)
+	}
+}
+
+func TestBufferDrainRate(t *testing.T) {
+	buf := New[int]()
+	for i := 0; i < 5; i++ {
+		buf.Write(i)
+	}
+	var got []int
+	start := time.Now()
+	err := buf.DrainRate(context.Background(), 100, func(i int) error {
+		got = append(got, i)
+		return nil
+	})
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("unexpected drained elements:\nexp=%v\ngot=%v", []int{0, 1, 2, 3, 4}, got)
+	}
+	// Four ticks of 10ms are needed after the first element
+	if elapsed < 35*time.Millisecond {
+		t.Errorf("drained too fast: %v", elapsed)
+	}
+}
+
+func TestBufferDrainRate_HighRate(t *testing.T) {
+	buf := New[int]()
+	for i := 0; i < 5; i++ {
+		buf.Write(i)
+	}
+	var n int
+	err := buf.DrainRate(context.Background(), 2e9, func(int) error {
+		n++
+		return nil
+	})
+	if err != nil || n != 5 {
+		t.Errorf("unexpected drain:\nexp=%d, %v\ngot=%d, %v", 5, nil, n, err)
+	}
+}
+
+func TestBufferDrainRate_Cancel(t *testing.T) {
+	buf := New[int]()
+	for i := 0; i < 5; i++ {
+		buf.Write(i)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	err := buf.DrainRate(ctx, 1, func(i int) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", context.Canceled, err)
+	}
+	if n := buf.Buffered(); n != 4 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 4, n)
+	}
+}
@@ -0,0 +1,36 @@
+package gobuffer
+
+// Route holds a destination Buffer for a fan-out writer (see FanOutRoutes) and an optional filter. Only elements
+// accepted by the filter are written to the destination. If the filter is nil then all elements are written.
+type Route[T any] struct {
+	Dst    *Buffer[T]
+	Filter func(T) bool
+}
+
+// fanOut is a Writer duplicating each written element into several buffers.
+type fanOut[T any] struct {
+	routes []Route[T]
+}
+
+// FanOut creates a Writer duplicating each written element into all the provided buffers.
+func FanOut[T any](dsts ...*Buffer[T]) Writer[T] {
+	routes := make([]Route[T], len(dsts))
+	for i, dst := range dsts {
+		routes[i] = Route[T]{Dst: dst}
+	}
+	return FanOutRoutes(routes...)
+}
+
+// FanOutRoutes creates a Writer duplicating each written element into the destination buffers of the provided
+// routes accepting the element.
+func FanOutRoutes[T any](routes ...Route[T]) Writer[T] {
+	return &fanOut[T]{routes: routes}
+}
+
+func (f *fanOut[T]) Write(element T) {
+	for _, route := range f.routes {
+		if route.Filter == nil || route.Filter(element) {
+			route.Dst.Write(element)
+		}
+	}
+}
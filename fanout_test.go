@@ -0,0 +1,33 @@
+package gobuffer
+
+import "testing"
+
+func TestFanOut(t *testing.T) {
+	a, b := New[rune](), New[rune]()
+	w := FanOut(a, b)
+	for _, r := range "abc" {
+		w.Write(r)
+	}
+	for _, buf := range []*Buffer[rune]{a, b} {
+		if got := readAll(buf); got != "abc" {
+			t.Errorf("unexpected content:\nexp=%s\ngot=%s", "abc", got)
+		}
+	}
+}
+
+func TestFanOutRoutes(t *testing.T) {
+	all, vowels := New[rune](), New[rune]()
+	w := FanOutRoutes(
+		Route[rune]{Dst: all},
+		Route[rune]{Dst: vowels, Filter: func(r rune) bool { return r == 'a' || r == 'e' }},
+	)
+	for _, r := range "gobuffer and parser" {
+		w.Write(r)
+	}
+	if got := readAll(all); got != "gobuffer and parser" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "gobuffer and parser", got)
+	}
+	if got := readAll(vowels); got != "eaae" {
+		t.Errorf("unexpected filtered content:\nexp=%s\ngot=%s", "eaae", got)
+	}
+}
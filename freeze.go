@@ -0,0 +1,23 @@
+package gobuffer
+
+// Freeze makes the Buffer read-only. After the Buffer has been frozen any attempt to add or remove unconsumed
+// elements (e.g. Buffer.Write, Buffer.MergeFrom or Buffer.Truncate) is a misuse with the error FrozenError and has
+// no effect (see MisusePolicy). Reading, rollback and commit are still allowed. A Buffer can't be unfrozen.
+func (b *Buffer[T]) Freeze() {
+	b.frozen = true
+}
+
+// Frozen returns true if the Buffer has been frozen (see Buffer.Freeze).
+func (b *Buffer[T]) Frozen() bool {
+	return b.frozen
+}
+
+// checkWritable returns true if the Buffer may be written to by the method op. Writing to a frozen Buffer is
+// handled as a misuse (see MisusePolicy).
+func (b *Buffer[T]) checkWritable(op string) bool {
+	if b.frozen {
+		_ = b.misused(op, FrozenError, false)
+		return false
+	}
+	return true
+}
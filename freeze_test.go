@@ -0,0 +1,46 @@
+package gobuffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBufferFreeze(t *testing.T) {
+	writes := []struct {
+		name  string
+		write func(buf *Buffer[rune])
+	}{
+		{"write", func(buf *Buffer[rune]) { buf.Write('x') }},
+		{"merge", func(buf *Buffer[rune]) {
+			other := New[rune]()
+			other.Write('x')
+			buf.MergeFrom(other)
+		}},
+		{"truncate", func(buf *Buffer[rune]) { buf.Truncate(0) }},
+	}
+	for _, test := range writes {
+		t.Run(test.name, func(t *testing.T) {
+			buf := NewWithSize[rune](3, 1)
+			for _, r := range "abcd" {
+				buf.Write(r)
+			}
+			buf.WithMisusePolicy(MisusePanic).Freeze()
+			if !buf.Frozen() {
+				t.Fatalf("expected buffer to be frozen")
+			}
+			func() {
+				defer func() {
+					if err, _ := recover().(error); !errors.Is(err, FrozenError) {
+						t.Errorf("unexpected panic:\nexp=%v\ngot=%v", FrozenError, err)
+					}
+				}()
+				test.write(buf)
+			}()
+			// Reading is still allowed
+			if got := readAll(buf); got != "abcd" {
+				t.Errorf("unexpected content:\nexp=%s\ngot=%s", "abcd", got)
+			}
+			buf.Commit()
+		})
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/habak67/gobuffer
 
-go 1.22
+go 1.23
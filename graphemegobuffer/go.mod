@@ -0,0 +1,10 @@
+module github.com/habak67/gobuffer/graphemegobuffer
+
+go 1.23
+
+require (
+	github.com/habak67/gobuffer v0.0.0
+	github.com/rivo/uniseg v0.4.7
+)
+
+replace github.com/habak67/gobuffer => ../
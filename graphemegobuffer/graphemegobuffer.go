@@ -0,0 +1,136 @@
+// Package graphemegobuffer provides gobuffer buffers of grapheme clusters (user-perceived characters) as defined
+// by Unicode Standard Annex #29. A Source segments a stream of bytes (or runes) into grapheme clusters, and a
+// Buffer pulling from a Source provides lookahead and rollback on cluster boundaries.
+//
+// The adapter is kept in a separate module to avoid forcing the segmentation dependency
+// (github.com/rivo/uniseg) on all users of the gobuffer module.
+package graphemegobuffer
+
+import (
+	"io"
+	"unicode/utf8"
+
+	"github.com/habak67/gobuffer"
+	"github.com/rivo/uniseg"
+)
+
+// readSize is the number of bytes read from the underlying reader at a time.
+const readSize = 4096
+
+// Source is a gobuffer.Source of grapheme clusters segmented from UTF-8 encoded text read from an io.Reader.
+// Each element is a string holding one grapheme cluster. A cluster is only returned when the text following it
+// has been read (or the end of the reader has been reached). Hence, a cluster is never split between reads.
+type Source struct {
+	r     io.Reader
+	data  []byte // data holds text read but not yet segmented.
+	state int    // state holds the segmentation state after the last returned cluster.
+	err   error  // err holds the error returned by the reader (io.EOF at the end).
+}
+
+var _ gobuffer.Source[string] = (*Source)(nil)
+
+// NewSource creates a Source segmenting the UTF-8 encoded text read from the provided reader.
+func NewSource(r io.Reader) *Source {
+	return &Source{r: r, state: -1}
+}
+
+// NewRuneSource creates a Source segmenting the runes read from the provided rune source.
+func NewRuneSource(src gobuffer.Source[rune]) *Source {
+	return NewSource(&runeReader{src: src})
+}
+
+// NewBuffer creates a gobuffer.Buffer of the grapheme clusters in the UTF-8 encoded text read from the provided
+// reader (see gobuffer.NewFromSource).
+func NewBuffer(r io.Reader) *gobuffer.Buffer[string] {
+	return gobuffer.NewFromSource[string](NewSource(r))
+}
+
+// Read reads up to len(p) grapheme clusters into p. At the end of the text io.EOF is returned. If the reader
+// fails then the clusters segmented before the failure are returned before the reader error.
+func (s *Source) Read(p []string) (n int, err error) {
+	for n < len(p) {
+		// Only segment complete runes unless there is no more text to read
+		text := s.data
+		if s.err == nil {
+			text = text[:completeRunes(text)]
+		}
+		if len(text) > 0 {
+			cluster, rest, _, state := uniseg.Step(text, s.state)
+			if len(rest) > 0 || s.err != nil {
+				p[n] = string(cluster)
+				n++
+				s.data = s.data[len(cluster):]
+				s.state = state
+				continue
+			}
+		}
+		if s.err != nil {
+			if n == 0 {
+				return 0, s.err
+			}
+			return n, nil
+		}
+		if n > 0 {
+			// Don't block on the reader when there are clusters to return
+			return n, nil
+		}
+		s.fill()
+	}
+	return n, nil
+}
+
+// fill reads more text from the reader.
+func (s *Source) fill() {
+	if len(s.data) > 0 && cap(s.data)-len(s.data) < readSize {
+		// Move the unsegmented text to a new buffer
+		s.data = append(make([]byte, 0, len(s.data)+readSize), s.data...)
+	} else if len(s.data) == 0 && cap(s.data) < readSize {
+		s.data = make([]byte, 0, readSize)
+	}
+	for i := 0; i < 100; i++ {
+		read, err := s.r.Read(s.data[len(s.data):cap(s.data)])
+		s.data = s.data[:len(s.data)+read]
+		if err != nil {
+			s.err = err
+			return
+		}
+		if read > 0 {
+			return
+		}
+	}
+	s.err = io.ErrNoProgress
+}
+
+// completeRunes returns the length of the longest prefix of the text not ending with an incomplete rune.
+func completeRunes(text []byte) int {
+	// An encoded rune is at most utf8.UTFMax bytes, so only the last bytes need to be checked
+	for i := len(text) - 1; i >= 0 && i >= len(text)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(text[i]) {
+			if utf8.FullRune(text[i:]) {
+				return len(text)
+			}
+			return i
+		}
+	}
+	return len(text)
+}
+
+// runeReader is an io.Reader returning the UTF-8 encoding of the runes read from a rune source.
+type runeReader struct {
+	src   gobuffer.Source[rune]
+	runes []rune
+}
+
+func (r *runeReader) Read(p []byte) (n int, err error) {
+	if len(p) < utf8.UTFMax {
+		return 0, io.ErrShortBuffer
+	}
+	if cap(r.runes) < len(p)/utf8.UTFMax {
+		r.runes = make([]rune, len(p)/utf8.UTFMax)
+	}
+	read, err := r.src.Read(r.runes[:len(p)/utf8.UTFMax])
+	for _, rn := range r.runes[:read] {
+		n += utf8.EncodeRune(p[n:], rn)
+	}
+	return n, err
+}
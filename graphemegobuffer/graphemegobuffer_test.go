@@ -0,0 +1,109 @@
+package graphemegobuffer
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+const text = "é👍🏽🇸🇪👨‍👩‍👧x\r\n"
+
+var clusters = []string{"é", "👍🏽", "🇸🇪", "👨‍👩‍👧", "x", "\r\n"}
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name string
+		r    io.Reader
+	}{
+		{"single read", strings.NewReader(text)},
+		{"one byte reads", iotest.OneByteReader(strings.NewReader(text))},
+		{"data with EOF", iotest.DataErrReader(strings.NewReader(text))},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := readClusters(NewSource(test.r))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, clusters) {
+				t.Errorf("unexpected clusters:\nexp=%q\ngot=%q", clusters, got)
+			}
+		})
+	}
+}
+
+func TestSource_ReaderError(t *testing.T) {
+	failure := errors.New("connection reset")
+	r := io.MultiReader(strings.NewReader("ab"), iotest.ErrReader(failure))
+	got, err := readClusters(NewSource(r))
+	if !errors.Is(err, failure) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", failure, err)
+	}
+	if !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("unexpected clusters:\nexp=%q\ngot=%q", []string{"a", "b"}, got)
+	}
+}
+
+// runeSource is a gobuffer.Source of runes returning one rune per read.
+type runeSource struct {
+	runes []rune
+}
+
+func (s *runeSource) Read(p []rune) (int, error) {
+	if len(s.runes) == 0 {
+		return 0, io.EOF
+	}
+	p[0] = s.runes[0]
+	s.runes = s.runes[1:]
+	return 1, nil
+}
+
+func TestNewRuneSource(t *testing.T) {
+	got, err := readClusters(NewRuneSource(&runeSource{runes: []rune(text)}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(got, clusters) {
+		t.Errorf("unexpected clusters:\nexp=%q\ngot=%q", clusters, got)
+	}
+}
+
+func TestNewBuffer(t *testing.T) {
+	buf := NewBuffer(iotest.OneByteReader(strings.NewReader(text)))
+	buf.Next()
+	buf.Consume()
+	state := buf.State()
+	for range 2 {
+		buf.Next()
+		buf.Consume()
+	}
+	if err := buf.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	var got []string
+	for cluster, ok := buf.Next(); ok; cluster, ok = buf.Next() {
+		got = append(got, cluster)
+		buf.Consume()
+	}
+	if !slices.Equal(got, clusters[1:]) {
+		t.Errorf("unexpected clusters:\nexp=%q\ngot=%q", clusters[1:], got)
+	}
+}
+
+func readClusters(src *Source) ([]string, error) {
+	var clusters []string
+	p := make([]string, 2)
+	for {
+		n, err := src.Read(p)
+		clusters = append(clusters, p[:n]...)
+		if err == io.EOF {
+			return clusters, nil
+		}
+		if err != nil {
+			return clusters, err
+		}
+	}
+}
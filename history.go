@@ -0,0 +1,37 @@
+package gobuffer
+
+import (
+	"errors"
+)
+
+var IllegalRewindError = errors.New("rewind beyond retained history")
+
+// WithHistory makes Buffer.Commit retain (at least) the n elements consumed last. The retained elements may be
+// read again by moving the read position back (see Buffer.RewindBy). The Buffer is returned. If n is < 0 then a
+// panic is raised.
+func (b *Buffer[T]) WithHistory(n int) *Buffer[T] {
+	if n < 0 {
+		panic(argumentError{"illegal negative history size", n})
+	}
+	b.history = n
+	return b
+}
+
+// RewindBy moves the read position n elements back. That is, the n elements consumed last will be read again. The
+// elements must still be retained by the Buffer, also across commits if the Buffer retains history (see
+// Buffer.WithHistory).
+//
+// If the elements are not retained (or have been passed to the consumed hook, see Buffer.OnConsumed) then an
+// IllegalRewindError is returned and the read position is not changed. If n is < 0 then a panic is raised.
+func (b *Buffer[T]) RewindBy(n int) error {
+	if n < 0 {
+		panic(argumentError{"illegal negative rewind", n})
+	}
+	pos := b.read.AbsolutePos() - n
+	if pos < b.startPos().AbsolutePos() || (b.onConsumed != nil && pos < b.released) {
+		return b.misused("RewindBy", IllegalRewindError, true)
+	}
+	b.read = b.read.Move(-n)
+	b.observe(Event{Kind: RollbackEvent, Elements: n})
+	return nil
+}
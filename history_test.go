@@ -0,0 +1,55 @@
+package gobuffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBufferRewindBy(t *testing.T) {
+	buf := NewWithSize[rune](3, 1).WithHistory(4)
+	for _, r := range "ls -l\npwd\n" {
+		buf.Write(r)
+	}
+	for i := 0; i < 8; i++ {
+		buf.Consume()
+	}
+	buf.Commit()
+	// The last 4 consumed elements are retained across the commit
+	if err := buf.RewindBy(4); err != nil {
+		t.Fatalf("unexpected rewind error: %v", err)
+	}
+	if got := readAll(buf); got != "l\npwd\n" {
+		t.Errorf("unexpected content:\nexp=%q\ngot=%q", "l\npwd\n", got)
+	}
+	buf.Commit()
+	if err := buf.RewindBy(7); !errors.Is(err, IllegalRewindError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", IllegalRewindError, err)
+	}
+	if n := buf.Buffered(); n != 0 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 0, n)
+	}
+}
+
+func TestBufferRewindBy_NoHistory(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abcde" {
+		buf.Write(r)
+	}
+	for i := 0; i < 3; i++ {
+		buf.Consume()
+	}
+	// Without commit all consumed elements are retained
+	if err := buf.RewindBy(3); err != nil {
+		t.Fatalf("unexpected rewind error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		buf.Consume()
+	}
+	buf.Commit()
+	if err := buf.RewindBy(2); !errors.Is(err, IllegalRewindError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", IllegalRewindError, err)
+	}
+	if err := buf.RewindBy(1); err != nil {
+		t.Errorf("unexpected rewind error: %v", err)
+	}
+}
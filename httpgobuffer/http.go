@@ -0,0 +1,47 @@
+// Package httpgobuffer provides net/http adapters for gobuffer. The adapters are kept in a separate package to
+// avoid linking net/http into every user of gobuffer.
+package httpgobuffer
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/habak67/gobuffer"
+)
+
+// replayBody is a request or response body replaying sniffed bytes before continuing reading from the body.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// SniffBody reads up to n bytes from the provided body and returns them together with a body replaying the
+// sniffed bytes before continuing reading from the provided body. Closing the returned body closes the provided
+// body. Only the sniffed bytes are held in memory. If the body ends before n bytes have been read then the bytes
+// read are returned without error.
+func SniffBody(body io.ReadCloser, n int) ([]byte, io.ReadCloser, error) {
+	prefix, r, err := gobuffer.Sniff(body, n)
+	return prefix, replayBody{Reader: r, Closer: body}, err
+}
+
+// SniffRequestBody sniffs up to n bytes from the body of the provided request (see SniffBody) and replaces the
+// request body with a body replaying the sniffed bytes.
+func SniffRequestBody(req *http.Request, n int) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	prefix, body, err := SniffBody(req.Body, n)
+	req.Body = body
+	return prefix, err
+}
+
+// SniffResponseBody sniffs up to n bytes from the body of the provided response (see SniffBody) and replaces the
+// response body with a body replaying the sniffed bytes.
+func SniffResponseBody(resp *http.Response, n int) ([]byte, error) {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil, nil
+	}
+	prefix, body, err := SniffBody(resp.Body, n)
+	resp.Body = body
+	return prefix, err
+}
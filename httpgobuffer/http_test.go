@@ -0,0 +1,57 @@
+package httpgobuffer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSniffRequestBody(t *testing.T) {
+	const body = `{"event":"push"}`
+	req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(body))
+	prefix, err := SniffRequestBody(req, 512)
+	if err != nil {
+		t.Fatalf("unexpected sniff error: %v", err)
+	}
+	if ct := http.DetectContentType(prefix); ct != "text/plain; charset=utf-8" {
+		t.Errorf("unexpected content type: %s", ct)
+	}
+	got, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if string(got) != body {
+		t.Errorf("unexpected body:\nexp=%s\ngot=%s", body, got)
+	}
+	if err := req.Body.Close(); err != nil {
+		t.Errorf("unexpected close error: %v", err)
+	}
+}
+
+func TestSniffResponseBody(t *testing.T) {
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader("%PDF-1.7 document"))}
+	prefix, err := SniffResponseBody(resp, 5)
+	if err != nil {
+		t.Fatalf("unexpected sniff error: %v", err)
+	}
+	if string(prefix) != "%PDF-" {
+		t.Errorf("unexpected prefix:\nexp=%s\ngot=%s", "%PDF-", prefix)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	if string(got) != "%PDF-1.7 document" {
+		t.Errorf("unexpected body:\nexp=%s\ngot=%s", "%PDF-1.7 document", got)
+	}
+}
+
+func TestSniffRequestBody_NoBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	prefix, err := SniffRequestBody(req, 8)
+	if err != nil || prefix != nil {
+		t.Errorf("unexpected sniff result: %q, %v", prefix, err)
+	}
+	if req.Body != http.NoBody {
+		t.Errorf("expected request body to be unchanged")
+	}
+}
@@ -0,0 +1,47 @@
+package gobuffer
+
+import "io"
+
+// Writer is implemented by types that elements may be written to. Buffer implements Writer.
+type Writer[T any] interface {
+	// Write writes an element.
+	Write(element T)
+}
+
+// Peeker is implemented by types providing one element lookahead. Buffer implements Peeker.
+type Peeker[T any] interface {
+	// Next returns the next element without consuming it. If there is no next element then false is returned.
+	Next() (T, bool)
+}
+
+// Reader is implemented by types providing one element lookahead using the next/consume pattern. Buffer and all
+// views implement Reader.
+type Reader[T any] interface {
+	Peeker[T]
+	// Consume consumes the next element (returned by Next).
+	Consume()
+}
+
+// Rollbacker is implemented by types supporting rollback to a saved state. Buffer and all views implement
+// Rollbacker.
+type Rollbacker interface {
+	// State returns a state that may be used to roll back to the current state.
+	State() State
+	// Rollback resets the read state to the provided state.
+	Rollback(state State) error
+}
+
+var (
+	_ Writer[any]    = (*Buffer[any])(nil)
+	_ Reader[any]    = (*Buffer[any])(nil)
+	_ Rollbacker     = (*Buffer[any])(nil)
+	_ Writer[any]    = (*SyncBuffer[any])(nil)
+	_ Reader[any]    = (*SyncBuffer[any])(nil)
+	_ Rollbacker     = (*SyncBuffer[any])(nil)
+	_ View[any]      = (*Buffer[any])(nil)
+	_ Peeker[byte]   = (*ByteBuffer)(nil)
+	_ io.Reader      = (*ByteBuffer)(nil)
+	_ io.ByteScanner = (*ByteBuffer)(nil)
+	_ io.ReaderFrom  = (*ByteBuffer)(nil)
+	_ View[any]      = (*TimedBuffer[any])(nil)
+)
@@ -0,0 +1,124 @@
+package gobuffer
+
+import (
+	"io"
+	"iter"
+)
+
+// Chunks returns an iterator over the unconsumed elements in the Buffer. The elements are yielded as row-aligned
+// slices referencing the Buffer rows (no copying). That is, each yielded slice holds the unconsumed elements in
+// a single Buffer row. Iterating the chunks doesn't consume any elements.
+//
+// The yielded slices are only valid until the Buffer is modified. The slices may be modified in place but must
+// not be retained after the iteration.
+func (b *Buffer[T]) Chunks() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		b.unshare()
+		b.chunks(b.read, b.write, yield)
+	}
+}
+
+// All returns an iterator over the unconsumed elements in the Buffer. Iterating the elements doesn't consume any
+// elements. The Buffer must not be modified during the iteration.
+func (b *Buffer[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for chunk := range b.readChunks() {
+			for _, element := range chunk {
+				if !yield(element) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// readChunks returns an iterator over the unconsumed elements in the Buffer like Buffer.Chunks. The yielded
+// slices may be shared with snapshots (see Buffer.Snapshot) and must not be modified.
+func (b *Buffer[T]) readChunks() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		b.chunks(b.read, b.write, yield)
+	}
+}
+
+// Chunked returns an iterator over groups of exactly n unconsumed elements in the Buffer. Each group is consumed
+// when it is yielded. Any incomplete trailing group (less than n elements) is left unconsumed. The yielded groups
+// are copies and may be retained.
+//
+// If n is <= 0 then a panic is raised.
+func (b *Buffer[T]) Chunked(n int) iter.Seq[[]T] {
+	if n <= 0 {
+		panic(argumentError{"illegal non-positive group size", n})
+	}
+	return func(yield func([]T) bool) {
+		for b.Buffered() >= n {
+			group := make([]T, 0, n)
+			b.chunks(b.read, b.read.Move(n), func(chunk []T) bool {
+				group = append(group, chunk...)
+				return true
+			})
+			b.consume(n)
+			if !yield(group) {
+				return
+			}
+		}
+	}
+}
+
+// chunks calls fn with row-aligned slices holding the elements between the positions from (inclusive) and
+// to (exclusive). The iteration stops if fn returns false.
+func (b *Buffer[T]) chunks(from, to position, fn func([]T) bool) {
+	end := to.AbsolutePos()
+	for pos := from; pos.AbsolutePos() < end; {
+		row, col := b.bufferPos(pos)
+		n := min(b.rowSize-col, end-pos.AbsolutePos())
+		if !fn(b.buffers[row][col : col+n : col+n]) {
+			return
+		}
+		pos = pos.Move(n)
+	}
+}
+
+// NewFromSeq creates a new Buffer holding all elements of the provided sequence. The sequence is consumed before
+// NewFromSeq returns. See NewSeqSource for a Buffer pulling from the sequence on demand.
+func NewFromSeq[T any](seq iter.Seq[T]) *Buffer[T] {
+	buf := New[T]()
+	for element := range seq {
+		buf.Write(element)
+	}
+	return buf
+}
+
+// SeqSource is a Source pulling elements from an iterator sequence on demand (see iter.Pull).
+type SeqSource[T any] struct {
+	next func() (T, bool)
+	stop func()
+}
+
+// NewSeqSource creates a new SeqSource pulling elements from the provided sequence. A Buffer pulling from the
+// sequence only when it runs out of buffered elements is created by NewFromSource(NewSeqSource(seq)).
+//
+// If the Buffer is abandoned before the end of the sequence then SeqSource.Stop must be called to release the
+// resources held by the sequence.
+func NewSeqSource[T any](seq iter.Seq[T]) *SeqSource[T] {
+	next, stop := iter.Pull(seq)
+	return &SeqSource[T]{next: next, stop: stop}
+}
+
+// Read pulls the next element from the sequence into p. A single element is pulled per call. Hence, the sequence
+// is never advanced beyond the element needed by the Buffer. At the end of the sequence io.EOF is returned.
+func (s *SeqSource[T]) Read(p []T) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	element, ok := s.next()
+	if !ok {
+		return 0, io.EOF
+	}
+	p[0] = element
+	return 1, nil
+}
+
+// Stop stops the sequence. After Stop the SeqSource returns io.EOF. Stop may be called multiple times.
+func (s *SeqSource[T]) Stop() {
+	s.stop()
+}
@@ -0,0 +1,149 @@
+package gobuffer
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+func TestBufferChunks(t *testing.T) {
+	tests := []struct {
+		name    string
+		writes  int
+		consume int
+		exp     [][]int
+	}{
+		{"empty", 0, 0, nil},
+		{"single partial row", 2, 0, [][]int{{0, 1}}},
+		{"full rows", 6, 0, [][]int{{0, 1, 2}, {3, 4, 5}}},
+		{"consumed start", 7, 2, [][]int{{2}, {3, 4, 5}, {6}}},
+		{"all consumed", 4, 4, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := NewWithSize[int](3, 1)
+			for i := 0; i < test.writes; i++ {
+				buf.Write(i)
+			}
+			for i := 0; i < test.consume; i++ {
+				buf.Consume()
+			}
+			var got [][]int
+			for chunk := range buf.Chunks() {
+				got = append(got, chunk)
+			}
+			if !slices.EqualFunc(got, test.exp, slices.Equal) {
+				t.Errorf("unexpected chunks:\nexp=%v\ngot=%v", test.exp, got)
+			}
+			if n := buf.Buffered(); n != test.writes-test.consume {
+				t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", test.writes-test.consume, n)
+			}
+		})
+	}
+}
+
+func TestBufferChunked(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes int
+		n      int
+		exp    [][]int
+		rest   int
+	}{
+		{"empty", 0, 2, nil, 0},
+		{"incomplete", 2, 3, nil, 2},
+		{"exact", 6, 3, [][]int{{0, 1, 2}, {3, 4, 5}}, 0},
+		{"trailing", 7, 2, [][]int{{0, 1}, {2, 3}, {4, 5}}, 1},
+		{"across rows", 8, 4, [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}}, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := NewWithSize[int](3, 1)
+			for i := 0; i < test.writes; i++ {
+				buf.Write(i)
+			}
+			var got [][]int
+			for group := range buf.Chunked(test.n) {
+				got = append(got, group)
+			}
+			if !slices.EqualFunc(got, test.exp, slices.Equal) {
+				t.Errorf("unexpected groups:\nexp=%v\ngot=%v", test.exp, got)
+			}
+			if n := buf.Buffered(); n != test.rest {
+				t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", test.rest, n)
+			}
+		})
+	}
+}
+
+func TestBufferChunked_Break(t *testing.T) {
+	buf := NewWithSize[int](3, 1)
+	for i := 0; i < 6; i++ {
+		buf.Write(i)
+	}
+	for range buf.Chunked(2) {
+		break
+	}
+	if n := buf.Buffered(); n != 4 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 4, n)
+	}
+}
+
+func TestBufferChunked_NonPositivePanic(t *testing.T) {
+	defer func() { _ = recover() }()
+
+	_ = New[int]().Chunked(0)
+	t.Errorf("expected Chunked to panic")
+}
+
+func TestNewFromSeq(t *testing.T) {
+	buf := NewFromSeq(slices.Values([]rune("abc")))
+	if got := readAll(buf); got != "abc" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "abc", got)
+	}
+}
+
+func TestNewSeqSource(t *testing.T) {
+	pulled := 0
+	seq := func(yield func(int) bool) {
+		for i := range 10 {
+			pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	src := NewSeqSource(iter.Seq[int](seq))
+	defer src.Stop()
+	buf := NewFromSource[int](src)
+	for range 3 {
+		buf.Pop()
+	}
+	// The sequence is only advanced on demand
+	if pulled != 3 {
+		t.Errorf("unexpected pulled elements:\nexp=%d\ngot=%d", 3, pulled)
+	}
+	src.Stop()
+	if _, ok := buf.Next(); ok {
+		t.Errorf("unexpected next element after stop")
+	}
+}
+
+func TestBufferAll(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abcde" {
+		buf.Write(r)
+	}
+	buf.Pop()
+	if got := string(slices.Collect(buf.All())); got != "bcde" {
+		t.Errorf("unexpected elements:\nexp=%s\ngot=%s", "bcde", got)
+	}
+	for r := range buf.All() {
+		if r == 'c' {
+			break
+		}
+	}
+	if n := buf.Buffered(); n != 4 {
+		t.Errorf("unexpected buffered elements:\nexp=%d\ngot=%d", 4, n)
+	}
+}
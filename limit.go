@@ -0,0 +1,58 @@
+package gobuffer
+
+import "errors"
+
+var BufferFullError = errors.New("buffer full")
+
+// WithMaxElements limits the number of elements held by the Buffer to n. The held elements are the elements not
+// yet removed by Buffer.Commit (including consumed elements). When the limit is reached Buffer.TryWrite returns
+// BufferFullError, and Buffer.Write and Buffer.WriteSlice are a misuse with the error BufferFullError (see
+// MisusePolicy). Hence, the Buffer never grows beyond the limit. The Buffer is returned.
+//
+// The limit applies to Buffer.Write, Buffer.WriteSlice and Buffer.TryWrite as well as to the byte writers (see
+// AsWriter and ByteBuffer.ReadFrom), which write the bytes that fit and return BufferFullError. Elements are not
+// pulled from the source of a full Buffer (see NewFromSource). Instead Buffer.Next returns false and Buffer.Err
+// returns BufferFullError until there is room again. The limit doesn't apply to elements merged from another
+// Buffer. If n is <= 0 then a panic is raised.
+func (b *Buffer[T]) WithMaxElements(n int) *Buffer[T] {
+	if n <= 0 {
+		panic(argumentError{"illegal non-positive max elements", n})
+	}
+	b.maxElements = n
+	return b
+}
+
+// TryWrite writes an element to the Buffer like Buffer.Write. If the Buffer holds the maximum number of elements
+// (see Buffer.WithMaxElements) then the element isn't written and BufferFullError is returned. If the Buffer is
+// frozen then FrozenError is returned (see Buffer.Freeze).
+func (b *Buffer[T]) TryWrite(element T) error {
+	if b.frozen {
+		return b.misused("TryWrite", FrozenError, true)
+	}
+	if b.maxElements > 0 && b.room() < 1 {
+		return b.full()
+	}
+	b.Write(element)
+	return nil
+}
+
+// room returns the number of elements that may be written before the Buffer holds the maximum number of elements.
+func (b *Buffer[T]) room() int {
+	return b.maxElements - (b.write.AbsolutePos() - b.startPos().AbsolutePos())
+}
+
+// checkRoom returns true if n elements may be written to the Buffer by the method op. Writing beyond the maximum
+// number of elements is handled as a misuse (see MisusePolicy).
+func (b *Buffer[T]) checkRoom(op string, n int) bool {
+	if b.room() < n {
+		_ = b.misused(op, b.full(), false)
+		return false
+	}
+	return true
+}
+
+// full notifies the observer about the Buffer being full (see LimitEvent) and returns BufferFullError.
+func (b *Buffer[T]) full() error {
+	b.observe(Event{Kind: LimitEvent, Err: BufferFullError})
+	return BufferFullError
+}
@@ -0,0 +1,102 @@
+package gobuffer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBufferWithMaxElements(t *testing.T) {
+	buf := NewWithSize[rune](2, 1).WithMaxElements(4)
+	for _, r := range "abc" {
+		if err := buf.TryWrite(r); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+	buf.WriteSlice([]rune("de"))
+	if err := buf.Misuse(); !errors.Is(err, BufferFullError) {
+		t.Errorf("unexpected misuse:\nexp=%v\ngot=%v", BufferFullError, err)
+	}
+	buf.Write('d')
+	if err := buf.TryWrite('e'); !errors.Is(err, BufferFullError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", BufferFullError, err)
+	}
+	// Consumed elements are held until committed
+	buf.Pop()
+	buf.Pop()
+	if err := buf.TryWrite('e'); !errors.Is(err, BufferFullError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", BufferFullError, err)
+	}
+	buf.Commit()
+	if err := buf.TryWrite('e'); err != nil {
+		t.Errorf("unexpected write error: %v", err)
+	}
+	if got := readAll(buf); got != "cde" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "cde", got)
+	}
+}
+
+func TestBufferWithMaxElements_ByteWriters(t *testing.T) {
+	buf := NewWithSize[byte](2, 1).WithMaxElements(5)
+	n, err := AsWriter(buf).Write([]byte("abc"))
+	if n != 3 || err != nil {
+		t.Fatalf("unexpected write:\nexp=%d, %v\ngot=%d, %v", 3, nil, n, err)
+	}
+	// A write beyond the limit is short
+	n, err = AsWriter(buf).Write([]byte("def"))
+	if n != 2 || !errors.Is(err, BufferFullError) {
+		t.Errorf("unexpected write:\nexp=%d, %v\ngot=%d, %v", 2, BufferFullError, n, err)
+	}
+	// ReadFrom stops when the limit is reached
+	b := NewByteBufferWithSize(2, 1)
+	b.WithMaxElements(5)
+	m, err := b.ReadFrom(strings.NewReader("abcdefgh"))
+	if m != 5 || !errors.Is(err, BufferFullError) {
+		t.Errorf("unexpected read:\nexp=%d, %v\ngot=%d, %v", 5, BufferFullError, m, err)
+	}
+	if got := string(b.ToSlice()); got != "abcde" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "abcde", got)
+	}
+}
+
+func TestBufferWithMaxElements_Source(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefetch int
+	}{
+		{name: "direct"},
+		{name: "prefetch", prefetch: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := &sliceSource[rune]{elements: []rune("abcdefgh"), max: 8}
+			buf := NewFromSourceWithSize[rune](src, 1, 4).WithMaxElements(3)
+			if test.prefetch > 0 {
+				buf.WithPrefetch(test.prefetch)
+				defer buf.StopPrefetch()
+			}
+			// Pulling stops at the limit
+			if err := buf.EnsureBuffered(5); !errors.Is(err, BufferFullError) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", BufferFullError, err)
+			}
+			if got := buf.Buffered(); got != 3 {
+				t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 3, got)
+			}
+			// Committing makes room for more elements
+			buf.ConsumeN(3)
+			buf.Commit()
+			var got []rune
+			for r, ok := buf.Next(); ok; r, ok = buf.Next() {
+				got = append(got, r)
+				buf.Consume()
+				buf.Commit()
+			}
+			if string(got) != "defgh" {
+				t.Errorf("unexpected content:\nexp=%s\ngot=%s", "defgh", string(got))
+			}
+			if err := buf.Err(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
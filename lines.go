@@ -0,0 +1,55 @@
+package gobuffer
+
+import "sort"
+
+// Lines tracks the lines of the text written to a rune or byte Buffer (see TrackLines). Lines maps positions (see
+// Position) to line and column numbers. Unlike the elements of the Buffer the line starts are never removed. That
+// is, Lines holds one offset for each line written to the Buffer.
+type Lines struct {
+	base    int   // base holds the offset of the first line.
+	starts  []int // starts holds the offsets of the lines after the first line.
+	written int   // written holds the offset after the last element seen.
+	cancel  func()
+}
+
+// TrackLines starts tracking the lines of the text written to the Buffer. The first line starts at the first
+// element still available in the Buffer. Lines are separated by '\n'. The tracking is stopped by Lines.Stop.
+//
+// Elements discarded from the back of the Buffer (see Buffer.Truncate) or written to the front of the Buffer (see
+// Buffer.WriteFront) while tracking make the tracked lines out of sync with the Buffer.
+func TrackLines[T rune | byte](buf *Buffer[T]) *Lines {
+	l := &Lines{base: buf.startPos().AbsolutePos(), written: buf.startPos().AbsolutePos()}
+	track := func(element T) {
+		l.written++
+		if element == '\n' {
+			l.starts = append(l.starts, l.written)
+		}
+	}
+	buf.chunks(buf.startPos(), buf.write, func(chunk []T) bool {
+		for _, element := range chunk {
+			track(element)
+		}
+		return true
+	})
+	l.cancel = buf.Watch(track)
+	return l
+}
+
+// LineCol returns the line and column (both starting at 1) of the provided position. The column is counted in
+// elements (runes or bytes). If the position is before the first tracked line then false is returned.
+func (l *Lines) LineCol(p Position) (line, col int, ok bool) {
+	if p.offset < l.base {
+		return
+	}
+	i := sort.SearchInts(l.starts, p.offset+1)
+	start := l.base
+	if i > 0 {
+		start = l.starts[i-1]
+	}
+	return i + 1, p.offset - start + 1, true
+}
+
+// Stop stops tracking the lines. The lines tracked so far are still available.
+func (l *Lines) Stop() {
+	l.cancel()
+}
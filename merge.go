@@ -0,0 +1,19 @@
+package gobuffer
+
+// MergeFrom consumes all unconsumed elements from the other Buffer and writes them to the Buffer. The elements are
+// copied row by row. The number of merged elements is returned.
+//
+// Merging a Buffer into itself is a no-op. Merging into a frozen Buffer is a misuse (see Buffer.Freeze) and
+// leaves the other Buffer untouched.
+func (b *Buffer[T]) MergeFrom(other *Buffer[T]) (n int) {
+	if other == b || !b.checkWritable("MergeFrom") {
+		return
+	}
+	other.chunks(other.read, other.write, func(chunk []T) bool {
+		b.writeSlice(chunk)
+		n += len(chunk)
+		return true
+	})
+	other.consume(n)
+	return
+}
@@ -0,0 +1,43 @@
+package gobuffer
+
+import "testing"
+
+func TestBufferMergeFrom(t *testing.T) {
+	dst := NewWithSize[rune](3, 1)
+	for _, r := range "ab" {
+		dst.Write(r)
+	}
+	dst.Consume()
+	src := NewWithSize[rune](4, 1)
+	for _, r := range "xcdefghij" {
+		src.Write(r)
+	}
+	src.Consume()
+	if n := dst.MergeFrom(src); n != 8 {
+		t.Errorf("unexpected merged:\nexp=%d\ngot=%d", 8, n)
+	}
+	if n := src.Buffered(); n != 0 {
+		t.Errorf("unexpected source buffered:\nexp=%d\ngot=%d", 0, n)
+	}
+	if got := readAll(dst); got != "bcdefghij" {
+		t.Errorf("unexpected merged content:\nexp=%s\ngot=%s", "bcdefghij", got)
+	}
+	if n := dst.MergeFrom(dst); n != 0 {
+		t.Errorf("unexpected self merged:\nexp=%d\ngot=%d", 0, n)
+	}
+}
+
+func TestBufferMergeFrom_Frozen(t *testing.T) {
+	dst := NewWithSize[rune](3, 1)
+	dst.Freeze()
+	src := NewWithSize[rune](4, 1)
+	for _, r := range "abc" {
+		src.Write(r)
+	}
+	if n := dst.MergeFrom(src); n != 0 {
+		t.Errorf("unexpected merged:\nexp=%d\ngot=%d", 0, n)
+	}
+	if got := readAll(src); got != "abc" {
+		t.Errorf("unexpected source content:\nexp=%s\ngot=%s", "abc", got)
+	}
+}
@@ -0,0 +1,59 @@
+package gobuffer
+
+import "errors"
+
+var EmptyConsumeError = errors.New("consume of empty buffer")
+
+// MisusePolicy controls how a Buffer handles misuse (see Buffer.WithMisusePolicy). Misuse is consuming from an
+// empty Buffer, rolling back to an invalid state (e.g. a state created by another Buffer), writing to a frozen
+// Buffer and writing to a full Buffer (see Buffer.WithMaxElements).
+type MisusePolicy int
+
+const (
+	// MisuseReturnError handles misuse by returning an error from methods returning errors (e.g. Buffer.Rollback).
+	// Misuse in other methods (e.g. Buffer.Write) is ignored and recorded (see Buffer.Misuse). This is the default
+	// policy and is intended for production.
+	MisuseReturnError MisusePolicy = iota
+	// MisusePanic handles misuse by panicking with a *MisuseError. The policy is intended for development to find
+	// misuse early.
+	MisusePanic
+)
+
+// MisuseError describes a misuse of a Buffer (see MisusePolicy). The underlying error (e.g. FrozenError) is
+// available using errors.Is.
+type MisuseError struct {
+	Op  string // Op holds the name of the misused method.
+	Err error
+}
+
+func (e *MisuseError) Error() string {
+	return "gobuffer: misuse of " + e.Op + ": " + e.Err.Error()
+}
+
+func (e *MisuseError) Unwrap() error {
+	return e.Err
+}
+
+// WithMisusePolicy configures how misuse of the Buffer is handled (see MisusePolicy). The Buffer is returned.
+func (b *Buffer[T]) WithMisusePolicy(policy MisusePolicy) *Buffer[T] {
+	b.misusePolicy = policy
+	return b
+}
+
+// Misuse returns the last misuse ignored by the Buffer (see MisuseReturnError) or nil if the Buffer has not been
+// misused. Misuse reported by an error returned from a method is not recorded.
+func (b *Buffer[T]) Misuse() error {
+	return b.misuse
+}
+
+// misused handles misuse of the method op according to the misuse policy. If the error is returned to the caller
+// then returned is true and the misuse is not recorded. The error to return (if any) is returned.
+func (b *Buffer[T]) misused(op string, err error, returned bool) error {
+	if b.misusePolicy == MisusePanic {
+		panic(&MisuseError{Op: op, Err: err})
+	}
+	if !returned {
+		b.misuse = &MisuseError{Op: op, Err: err}
+	}
+	return err
+}
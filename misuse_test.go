@@ -0,0 +1,70 @@
+package gobuffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBufferMisusePolicy(t *testing.T) {
+	foreign := NewWithSize[int](3, 1)
+	misuses := []struct {
+		name   string
+		misuse func(buf *Buffer[int]) error
+		err    error
+	}{
+		{"consume empty", func(buf *Buffer[int]) error {
+			buf.Consume()
+			buf.Consume()
+			return nil
+		}, EmptyConsumeError},
+		{"foreign state", func(buf *Buffer[int]) error { return buf.Rollback(foreign.State()) }, IllegalStateError},
+		{"zero state", func(buf *Buffer[int]) error { return buf.Rollback(State{}) }, ZeroStateError},
+		{"write frozen", func(buf *Buffer[int]) error {
+			buf.Freeze()
+			buf.Write(2)
+			return nil
+		}, FrozenError},
+	}
+	for _, test := range misuses {
+		t.Run(test.name+"/error", func(t *testing.T) {
+			buf := NewWithSize[int](2, 1)
+			buf.Write(1)
+			err := test.misuse(buf)
+			if err == nil {
+				err = buf.Misuse()
+			} else if buf.Misuse() != nil {
+				t.Errorf("unexpected recorded misuse: %v", buf.Misuse())
+			}
+			if !errors.Is(err, test.err) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", test.err, err)
+			}
+			if n := buf.TotalWritten(); n != 1 {
+				t.Errorf("unexpected written:\nexp=%d\ngot=%d", 1, n)
+			}
+		})
+		t.Run(test.name+"/panic", func(t *testing.T) {
+			buf := NewWithSize[int](2, 1).WithMisusePolicy(MisusePanic)
+			buf.Write(1)
+			defer func() {
+				var misuse *MisuseError
+				if err, _ := recover().(error); !errors.As(err, &misuse) || !errors.Is(misuse, test.err) {
+					t.Errorf("unexpected panic:\nexp=%v\ngot=%v", test.err, err)
+				}
+			}()
+			_ = test.misuse(buf)
+		})
+	}
+}
+
+func TestBufferMisusePolicy_ValidUse(t *testing.T) {
+	buf := NewWithSize[int](2, 1).WithMisusePolicy(MisusePanic)
+	state := buf.State()
+	buf.Write(1)
+	buf.Consume()
+	if err := buf.Rollback(state); err != nil {
+		t.Errorf("unexpected rollback error: %v", err)
+	}
+	if err := buf.Misuse(); err != nil {
+		t.Errorf("unexpected misuse: %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+// Package netgobuffer provides net adapters for gobuffer. The adapters are kept in a separate package to avoid
+// linking net into every user of gobuffer.
+package netgobuffer
+
+import (
+	"net"
+
+	"github.com/habak67/gobuffer"
+)
+
+// sniffedConn is a net.Conn replaying sniffed bytes before continuing reading from the connection.
+type sniffedConn struct {
+	net.Conn
+	r *gobuffer.RewindableReader
+}
+
+func (c *sniffedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// SniffConn reads up to n bytes from the provided connection and returns them together with a connection whose
+// Read first replays the sniffed bytes and then continues reading from the provided connection. All other methods
+// are delegated to the provided connection.
+//
+// If the connection is closed before n bytes have been read then the bytes read are returned without error.
+// Otherwise, any read error is returned together with the bytes read so far and the replaying connection.
+func SniffConn(conn net.Conn, n int) ([]byte, net.Conn, error) {
+	prefix, r, err := gobuffer.Sniff(conn, n)
+	return prefix, &sniffedConn{Conn: conn, r: r}, err
+}
@@ -0,0 +1,45 @@
+package netgobuffer
+
+import (
+	"io"
+	"net"
+	"testing"
+)
+
+func TestSniffConn(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		n      int
+		prefix string
+	}{
+		{"prefix", "\x16\x03\x01 client hello", 3, "\x16\x03\x01"},
+		{"short", "GE", 4, "GE"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			go func() {
+				_, _ = client.Write([]byte(test.data))
+				_ = client.Close()
+			}()
+			prefix, conn, err := SniffConn(server, test.n)
+			if err != nil {
+				t.Fatalf("unexpected sniff error: %v", err)
+			}
+			if string(prefix) != test.prefix {
+				t.Errorf("unexpected prefix:\nexp=%q\ngot=%q", test.prefix, prefix)
+			}
+			all, err := io.ReadAll(conn)
+			if err != nil {
+				t.Fatalf("unexpected read error: %v", err)
+			}
+			if string(all) != test.data {
+				t.Errorf("unexpected content:\nexp=%q\ngot=%q", test.data, all)
+			}
+			if conn.LocalAddr() != server.LocalAddr() {
+				t.Errorf("expected local address to be delegated")
+			}
+		})
+	}
+}
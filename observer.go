@@ -0,0 +1,72 @@
+package gobuffer
+
+// EventKind identifies the kind of an Event.
+type EventKind int
+
+const (
+	// CommitEvent is emitted by Buffer.Commit.
+	CommitEvent EventKind = iota
+	// RollbackEvent is emitted by Buffer.Rollback.
+	RollbackEvent
+	// GrowEvent is emitted when rows are added to a Buffer.
+	GrowEvent
+	// LimitEvent is emitted when elements are refused as the Buffer is full (see Buffer.WithMaxElements and
+	// SyncBuffer.WithMaxBuffered).
+	LimitEvent
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case CommitEvent:
+		return "commit"
+	case RollbackEvent:
+		return "rollback"
+	case GrowEvent:
+		return "grow"
+	case LimitEvent:
+		return "limit"
+	}
+	return "unknown"
+}
+
+// Event describes something that happened in a Buffer (see Observer).
+type Event struct {
+	Kind EventKind
+	// Rows holds the number of rows removed by a commit or added by a grow.
+	Rows int
+	// Elements holds the number of elements removed by a commit or the number of elements to read again after a
+	// rollback.
+	Elements int
+	// Buffered holds the number of unconsumed elements in the Buffer after the event.
+	Buffered int
+	// Err holds the error returned by a failed rollback or BufferFullError for a limit hit.
+	Err error
+}
+
+// Observer is notified about events in a Buffer. Observers are typically used for instrumentation (like tracing
+// or metrics) of the Buffer. An Observer is called synchronously and should return quickly.
+type Observer interface {
+	Observe(event Event)
+}
+
+// ObserverFunc is an adapter allowing the use of an ordinary function as an Observer.
+type ObserverFunc func(event Event)
+
+func (f ObserverFunc) Observe(event Event) {
+	f(event)
+}
+
+// WithObserver configures an Observer notified about events in the Buffer. The Buffer is returned to allow
+// chaining.
+func (b *Buffer[T]) WithObserver(o Observer) *Buffer[T] {
+	b.observer = o
+	return b
+}
+
+// observe notifies the configured observer (if any) about the event.
+func (b *Buffer[T]) observe(event Event) {
+	if b.observer != nil {
+		event.Buffered = b.Buffered()
+		b.observer.Observe(event)
+	}
+}
@@ -0,0 +1,62 @@
+package gobuffer
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBufferWithObserver(t *testing.T) {
+	var events []Event
+	buf := NewWithSize[int](2, 1).WithObserver(ObserverFunc(func(e Event) {
+		events = append(events, e)
+	}))
+	for i := 0; i < 5; i++ {
+		buf.Write(i)
+	}
+	state := buf.State()
+	for i := 0; i < 3; i++ {
+		buf.Consume()
+	}
+	_ = buf.Rollback(state)
+	for i := 0; i < 3; i++ {
+		buf.Consume()
+	}
+	buf.Commit()
+	_ = buf.Rollback(state)
+	exp := []Event{
+		{Kind: GrowEvent, Rows: 1, Buffered: 2},
+		{Kind: GrowEvent, Rows: 1, Buffered: 4},
+		{Kind: RollbackEvent, Elements: 3, Buffered: 5},
+		{Kind: CommitEvent, Rows: 1, Elements: 2, Buffered: 2},
+		{Kind: RollbackEvent, Buffered: 2, Err: IllegalStateError},
+	}
+	if !slices.EqualFunc(events, exp, func(a, b Event) bool {
+		return a.Kind == b.Kind && a.Rows == b.Rows && a.Elements == b.Elements && a.Buffered == b.Buffered &&
+			errors.Is(a.Err, b.Err)
+	}) {
+		t.Errorf("unexpected events:\nexp=%+v\ngot=%+v", exp, events)
+	}
+}
+
+func TestBufferWithObserver_Limit(t *testing.T) {
+	var events []Event
+	buf := NewWithSize[byte](2, 1).WithMaxElements(2).WithObserver(ObserverFunc(func(e Event) {
+		events = append(events, e)
+	}))
+	_ = buf.TryWrite('a')
+	_, _ = AsWriter(buf).Write([]byte("bc"))
+	_ = buf.TryWrite('d')
+	var limits int
+	for _, e := range events {
+		if e.Kind == LimitEvent {
+			limits++
+			if !errors.Is(e.Err, BufferFullError) || e.Buffered != 2 {
+				t.Errorf("unexpected limit event: %+v", e)
+			}
+		}
+	}
+	if limits != 2 {
+		t.Errorf("unexpected limit events:\nexp=%d\ngot=%d", 2, limits)
+	}
+}
@@ -0,0 +1,110 @@
+// Package otelgobuffer provides an OpenTelemetry integration for gobuffer buffers. The integration is implemented
+// as gobuffer.Observer instances recording buffer events as metrics (see NewMetrics) or span events (see
+// SpanEvents). Configure an observer using gobuffer.Buffer.WithObserver.
+//
+// The integration is kept in a separate module to avoid forcing the OpenTelemetry dependencies on all users of
+// the gobuffer module.
+package otelgobuffer
+
+import (
+	"context"
+
+	"github.com/habak67/gobuffer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metrics is a gobuffer.Observer recording buffer events as OpenTelemetry metrics. The following instruments
+// are recorded.
+//
+//	gobuffer.commits            Counter of commits.
+//	gobuffer.committed.elements Counter of elements removed by commits.
+//	gobuffer.rollbacks          Counter of rollbacks (with attribute error=true for failed rollbacks).
+//	gobuffer.grown.rows         Counter of rows added to buffers.
+//	gobuffer.limit.hits         Counter of times full buffers refused elements (see gobuffer.Buffer.WithMaxElements).
+//	gobuffer.buffered           Histogram of the number of unconsumed elements after each event.
+type Metrics struct {
+	commits   metric.Int64Counter
+	committed metric.Int64Counter
+	rollbacks metric.Int64Counter
+	grown     metric.Int64Counter
+	limits    metric.Int64Counter
+	buffered  metric.Int64Histogram
+	attrs     attribute.Set
+}
+
+// NewMetrics creates a Metrics observer creating its instruments using the provided meter. The provided attributes
+// are added to all measurements (typically used to identify the buffer).
+func NewMetrics(meter metric.Meter, attrs ...attribute.KeyValue) (m *Metrics, err error) {
+	m = &Metrics{attrs: attribute.NewSet(attrs...)}
+	if m.commits, err = meter.Int64Counter("gobuffer.commits",
+		metric.WithDescription("Number of buffer commits")); err != nil {
+		return nil, err
+	}
+	if m.committed, err = meter.Int64Counter("gobuffer.committed.elements",
+		metric.WithDescription("Number of elements removed by buffer commits")); err != nil {
+		return nil, err
+	}
+	if m.rollbacks, err = meter.Int64Counter("gobuffer.rollbacks",
+		metric.WithDescription("Number of buffer rollbacks")); err != nil {
+		return nil, err
+	}
+	if m.grown, err = meter.Int64Counter("gobuffer.grown.rows",
+		metric.WithDescription("Number of rows added to buffers")); err != nil {
+		return nil, err
+	}
+	if m.limits, err = meter.Int64Counter("gobuffer.limit.hits",
+		metric.WithDescription("Number of times full buffers refused elements")); err != nil {
+		return nil, err
+	}
+	if m.buffered, err = meter.Int64Histogram("gobuffer.buffered",
+		metric.WithDescription("Number of unconsumed buffer elements")); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Metrics) Observe(event gobuffer.Event) {
+	ctx := context.Background()
+	attrs := metric.WithAttributeSet(m.attrs)
+	switch event.Kind {
+	case gobuffer.CommitEvent:
+		m.commits.Add(ctx, 1, attrs)
+		m.committed.Add(ctx, int64(event.Elements), attrs)
+	case gobuffer.RollbackEvent:
+		m.rollbacks.Add(ctx, 1, attrs, metric.WithAttributes(attribute.Bool("error", event.Err != nil)))
+	case gobuffer.GrowEvent:
+		m.grown.Add(ctx, int64(event.Rows), attrs)
+	case gobuffer.LimitEvent:
+		m.limits.Add(ctx, 1, attrs)
+	}
+	m.buffered.Record(ctx, int64(event.Buffered), attrs)
+}
+
+// spanEvents is a gobuffer.Observer adding buffer events to a span.
+type spanEvents struct {
+	span trace.Span
+}
+
+// SpanEvents creates a gobuffer.Observer adding an event to the provided span for each buffer event. The event
+// name is "gobuffer." followed by the kind of the buffer event (like "gobuffer.commit") and the event attributes
+// hold the details of the buffer event.
+func SpanEvents(span trace.Span) gobuffer.Observer {
+	return spanEvents{span: span}
+}
+
+func (s spanEvents) Observe(event gobuffer.Event) {
+	if !s.span.IsRecording() {
+		return
+	}
+	attrs := []attribute.KeyValue{
+		attribute.Int("gobuffer.rows", event.Rows),
+		attribute.Int("gobuffer.elements", event.Elements),
+		attribute.Int("gobuffer.buffered", event.Buffered),
+	}
+	if event.Err != nil {
+		attrs = append(attrs, attribute.String("gobuffer.error", event.Err.Error()))
+	}
+	s.span.AddEvent("gobuffer."+event.Kind.String(), trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,77 @@
+package otelgobuffer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/habak67/gobuffer"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	observer, err := NewMetrics(provider.Meter("test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := gobuffer.NewWithSize[int](2, 1).WithMaxElements(5).WithObserver(observer)
+	for i := 0; i < 5; i++ {
+		buf.Write(i)
+	}
+	_ = buf.TryWrite(5)
+	state := buf.State()
+	buf.Consume()
+	buf.Consume()
+	buf.Commit()
+	_ = buf.Rollback(state)
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("unexpected collect error: %v", err)
+	}
+	exp := map[string]int64{
+		"gobuffer.commits":            1,
+		"gobuffer.committed.elements": 2,
+		"gobuffer.rollbacks":          1,
+		"gobuffer.grown.rows":         2,
+		"gobuffer.limit.hits":         1,
+	}
+	got := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range sum.DataPoints {
+					got[m.Name] += dp.Value
+				}
+			}
+		}
+	}
+	for name, v := range exp {
+		if got[name] != v {
+			t.Errorf("unexpected %s:\nexp=%d\ngot=%d", name, v, got[name])
+		}
+	}
+}
+
+func TestSpanEvents(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_, span := provider.Tracer("test").Start(context.Background(), "parse")
+	buf := gobuffer.NewWithSize[int](2, 1).WithObserver(SpanEvents(span))
+	buf.Write(1)
+	buf.Consume()
+	buf.Commit()
+	_ = buf.Rollback(gobuffer.State{})
+	span.End()
+	var names []string
+	for _, e := range recorder.Ended()[0].Events() {
+		names = append(names, e.Name)
+	}
+	exp := []string{"gobuffer.commit", "gobuffer.rollback"}
+	if len(names) != len(exp) || names[0] != exp[0] || names[1] != exp[1] {
+		t.Errorf("unexpected span events:\nexp=%v\ngot=%v", exp, names)
+	}
+}
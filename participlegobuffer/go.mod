@@ -0,0 +1,10 @@
+module github.com/habak67/gobuffer/participlegobuffer
+
+go 1.23
+
+require (
+	github.com/alecthomas/participle/v2 v2.1.4
+	github.com/habak67/gobuffer v0.0.0
+)
+
+replace github.com/habak67/gobuffer => ../
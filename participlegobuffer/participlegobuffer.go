@@ -0,0 +1,56 @@
+// Package participlegobuffer adapts gobuffer buffers to the lexer interfaces of the participle parser library
+// (github.com/alecthomas/participle). A Lexer lets a participle parser read tokens from a Buffer, and Fill lets a
+// Buffer be filled from a participle lexer.
+//
+// The adapter is kept in a separate module to avoid forcing the participle dependency on all users of the
+// gobuffer module.
+package participlegobuffer
+
+import (
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/habak67/gobuffer"
+)
+
+// Lexer is a participle lexer.Lexer returning the tokens written to a gobuffer.Buffer. When the Buffer holds no
+// more tokens an EOF token is returned. As participle never reads a token twice, each token is consumed and
+// committed when returned. Hence, the memory held by the Buffer is bounded by the number of tokens written ahead
+// of the parser.
+type Lexer struct {
+	buf *gobuffer.Buffer[lexer.Token]
+	pos lexer.Position // pos holds the position of the last returned token.
+}
+
+var _ lexer.Lexer = (*Lexer)(nil)
+
+// NewLexer creates a Lexer returning the tokens written to the provided Buffer.
+func NewLexer(buf *gobuffer.Buffer[lexer.Token]) *Lexer {
+	return &Lexer{buf: buf}
+}
+
+// Next consumes and returns the next token. If the Buffer holds no more tokens then an EOF token positioned at
+// the last returned token is returned.
+func (l *Lexer) Next() (lexer.Token, error) {
+	token, ok := l.buf.Next()
+	if !ok {
+		return lexer.EOFToken(l.pos), nil
+	}
+	l.buf.Consume()
+	l.buf.Commit()
+	l.pos = token.Pos
+	return token, nil
+}
+
+// Fill writes all tokens returned by the provided participle lexer to the Buffer. The EOF token ending the token
+// stream is not written. If the lexer returns an error then the filling stops and the error is returned.
+func Fill(buf *gobuffer.Buffer[lexer.Token], lex lexer.Lexer) error {
+	for {
+		token, err := lex.Next()
+		if err != nil {
+			return err
+		}
+		if token.EOF() {
+			return nil
+		}
+		buf.Write(token)
+	}
+}
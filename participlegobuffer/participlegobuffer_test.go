@@ -0,0 +1,45 @@
+package participlegobuffer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/habak67/gobuffer"
+)
+
+func TestFillAndLexer(t *testing.T) {
+	lex, err := lexer.TextScannerLexer.Lex("test", strings.NewReader("let x = 42"))
+	if err != nil {
+		t.Fatalf("unexpected lex error: %v", err)
+	}
+	buf := gobuffer.NewWithSize[lexer.Token](2, 1)
+	if err := Fill(buf, lex); err != nil {
+		t.Fatalf("unexpected fill error: %v", err)
+	}
+	if n := buf.Buffered(); n != 4 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 4, n)
+	}
+	adapter := NewLexer(buf)
+	var values []string
+	for {
+		token, err := adapter.Next()
+		if err != nil {
+			t.Fatalf("unexpected next error: %v", err)
+		}
+		if token.EOF() {
+			break
+		}
+		values = append(values, token.Value)
+	}
+	if got := strings.Join(values, " "); got != "let x = 42" {
+		t.Errorf("unexpected tokens:\nexp=%s\ngot=%s", "let x = 42", got)
+	}
+	if report := buf.RetentionReport(); report.Committable != 0 {
+		t.Errorf("unexpected committable rows:\nexp=%d\ngot=%d", 0, report.Committable)
+	}
+	// Reading past the end keeps returning EOF
+	if token, _ := adapter.Next(); !token.EOF() {
+		t.Errorf("unexpected token after EOF: %v", token)
+	}
+}
@@ -0,0 +1,287 @@
+package gobuffer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+var SnapshotFormatError = errors.New("illegal snapshot format")
+var SnapshotChecksumError = errors.New("snapshot checksum mismatch")
+
+// Codec encodes and decodes the elements of a Buffer when the Buffer is persisted (see Buffer.SaveTo and
+// RestoreFrom). Decode must read exactly the bytes written by Encode for an element.
+type Codec[T any] interface {
+	Encode(w io.Writer, element T) error
+	Decode(r io.Reader) (T, error)
+}
+
+// BinaryCodec is a Codec encoding elements using encoding/binary (little endian). The element type must be a
+// fixed-size value or a slice of fixed-size values as defined by encoding/binary (e.g. int32, rune or byte, but
+// not int).
+type BinaryCodec[T any] struct{}
+
+func (BinaryCodec[T]) Encode(w io.Writer, element T) error {
+	return binary.Write(w, binary.LittleEndian, element)
+}
+
+func (BinaryCodec[T]) Decode(r io.Reader) (element T, err error) {
+	err = binary.Read(r, binary.LittleEndian, &element)
+	return
+}
+
+const (
+	snapshotMagic = "GOBUF"
+	// snapshotVersion is the version of written snapshots. Version 1 snapshots (without row checksums) and
+	// version 2 snapshots (without flags) may still be restored.
+	snapshotVersion = 3
+	// snapshotCompressed is the snapshot flag telling that the rows are compressed.
+	snapshotCompressed = 1 << 0
+	// snapshotMaxRowSize, snapshotMaxRow, snapshotMaxFrameSize and snapshotMaxElements are the limits of a snapshot
+	// being restored. A malformed snapshot is never trusted to allocate more than the limits allow.
+	snapshotMaxRowSize   = 1 << 24
+	snapshotMaxRow       = 1<<31 - 1
+	snapshotMaxFrameSize = 1 << 28
+	snapshotMaxElements  = 1 << 40
+)
+
+// snapshotTable is the CRC-32 table used for the row checksums of a snapshot.
+var snapshotTable = crc32.MakeTable(crc32.Castagnoli)
+
+// SaveTo writes a snapshot of the Buffer to the provided writer. The elements are encoded using the provided
+// codec. The snapshot holds all elements still available in the Buffer (including consumed elements not yet
+// removed by a commit) together with the read and write positions. Hence, states created before the snapshot
+// was taken are valid also for the Buffer restored from the snapshot (see RestoreFrom).
+//
+// The snapshot consists of a header holding the Buffer geometry and positions followed by one frame for each
+// Buffer row holding elements. Each frame holds the number of elements in the row, the encoded elements and a
+// CRC-32 checksum of the frame validated when the snapshot is restored. If the codec compresses rows (see
+// CompressedCodec) then the encoded elements of each row are compressed.
+func (b *Buffer[T]) SaveTo(w io.Writer, codec Codec[T]) (err error) {
+	compressor := compressorOf(codec)
+	header := []byte(snapshotMagic)
+	header = append(header, snapshotVersion, 0)
+	if compressor != nil {
+		header[len(header)-1] |= snapshotCompressed
+	}
+	for _, v := range []int{b.rowSize, b.startRow, b.read.Row, b.read.Col, b.write.Row, b.write.Col} {
+		header = binary.AppendUvarint(header, uint64(v))
+	}
+	if _, err = w.Write(header); err != nil {
+		return
+	}
+	var row bytes.Buffer
+	var frame []byte
+	b.chunks(b.startPos(), b.write, func(chunk []T) bool {
+		row.Reset()
+		for _, element := range chunk {
+			if err = codec.Encode(&row, element); err != nil {
+				return false
+			}
+		}
+		data := row.Bytes()
+		if compressor != nil {
+			if data, err = compressor.Compress(data); err != nil {
+				return false
+			}
+		}
+		frame = binary.AppendUvarint(frame[:0], uint64(len(chunk)))
+		frame = binary.AppendUvarint(frame, uint64(len(data)))
+		frame = append(frame, data...)
+		frame = binary.LittleEndian.AppendUint32(frame, crc32.Checksum(frame, snapshotTable))
+		_, err = w.Write(frame)
+		return err == nil
+	})
+	return
+}
+
+// SaveFile writes a snapshot of the Buffer to the file with the provided path (see Buffer.SaveTo). The snapshot
+// is first written to a temporary file in the same directory which is then synced to disk and renamed to the
+// provided path. Hence, an existing snapshot file is never left partially overwritten.
+func (b *Buffer[T]) SaveFile(path string, codec Codec[T]) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+	w := bufio.NewWriter(f)
+	if err = b.SaveTo(w, codec); err != nil {
+		return
+	}
+	if err = w.Flush(); err != nil {
+		return
+	}
+	if err = f.Sync(); err != nil {
+		return
+	}
+	if err = f.Close(); err != nil {
+		return
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// RestoreFrom creates a new Buffer from a snapshot read from the provided reader (see Buffer.SaveTo). The elements
+// are decoded using the provided codec. The restored Buffer has the same geometry, content and read and write
+// positions as the Buffer when the snapshot was taken.
+//
+// If the snapshot is malformed then an error wrapping SnapshotFormatError is returned. A snapshot exceeding the
+// restore limits (e.g. a row size or row number out of range) is malformed. The rows are allocated as they are
+// read. Hence, a malformed snapshot never allocates memory beyond its actual content. If the checksum of a row
+// doesn't match the row content (the snapshot is corrupt) then an error wrapping SnapshotChecksumError is
+// returned. A corrupt row length may make the row appear truncated or out of range and is then reported as
+// malformed. Errors from the reader and the codec are returned as is.
+func RestoreFrom[T any](r io.Reader, codec Codec[T]) (*Buffer[T], error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, snapshotError(err)
+	}
+	if string(magic[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, detailError{err: SnapshotFormatError,
+			detail: "bad magic " + strconv.Quote(string(magic[:len(snapshotMagic)]))}
+	}
+	version := magic[len(snapshotMagic)]
+	if version < 1 || version > snapshotVersion {
+		return nil, detailError{err: SnapshotFormatError, detail: "unsupported version " + strconv.Itoa(int(version))}
+	}
+	var flags byte
+	if version >= 3 {
+		var err error
+		if flags, err = br.ReadByte(); err != nil {
+			return nil, snapshotError(err)
+		}
+	}
+	var compressor Compressor
+	if flags&snapshotCompressed != 0 {
+		if compressor = compressorOf(codec); compressor == nil {
+			return nil, detailError{err: SnapshotFormatError, detail: "compressed snapshot requires a compressing codec"}
+		}
+	}
+	var header [6]int
+	for i := range header {
+		v, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, snapshotError(err)
+		}
+		if v > snapshotMaxRow {
+			return nil, detailError{err: SnapshotFormatError,
+				detail: "header value " + strconv.FormatUint(v, 10) + " out of range"}
+		}
+		header[i] = int(v)
+	}
+	rowSize, startRow := header[0], header[1]
+	if rowSize <= 0 || rowSize > snapshotMaxRowSize {
+		return nil, detailError{err: SnapshotFormatError, detail: "illegal row size " + strconv.Itoa(rowSize)}
+	}
+	start := position{rowSize: rowSize, Row: startRow}
+	read := position{rowSize: rowSize, Row: header[2], Col: header[3]}
+	write := position{rowSize: rowSize, Row: header[4], Col: header[5]}
+	if read.Col >= rowSize || write.Col >= rowSize || read.Row < startRow ||
+		write.AbsolutePos() < read.AbsolutePos() {
+		return nil, detailError{err: SnapshotFormatError, detail: "illegal positions"}
+	}
+	if elements := write.AbsolutePos() - start.AbsolutePos(); elements > snapshotMaxElements {
+		return nil, detailError{err: SnapshotFormatError, detail: strconv.Itoa(elements) + " elements out of range"}
+	}
+	// The rows are allocated as the frames are read. Hence, a truncated snapshot claiming many elements doesn't
+	// allocate rows for them.
+	buf := NewWithSize[T](rowSize, 1)
+	buf.startRow = startRow
+	buf.read = read
+	buf.write = write
+	var data bytes.Buffer
+	for pos := start; pos.AbsolutePos() < write.AbsolutePos(); {
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, snapshotError(err)
+		}
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, snapshotError(err)
+		}
+		// The row length is checked before it is converted to int. Hence, a huge length can't wrap to a negative
+		// length moving the position backward.
+		if pos.Col != 0 || n == 0 || n > uint64(min(rowSize, write.AbsolutePos()-pos.AbsolutePos())) {
+			return nil, detailError{err: SnapshotFormatError, detail: "illegal row length " + strconv.FormatUint(n, 10)}
+		}
+		if size > snapshotMaxFrameSize {
+			return nil, detailError{err: SnapshotFormatError,
+				detail: "illegal row size " + strconv.FormatUint(size, 10) + " bytes"}
+		}
+		// The frame data is read incrementally. Hence, a truncated frame only allocates the bytes actually read.
+		data.Reset()
+		if _, err = io.CopyN(&data, br, int64(size)); err != nil {
+			return nil, snapshotError(err)
+		}
+		if version >= 2 {
+			if err = checkFrame(br, n, size, data.Bytes()); err != nil {
+				return nil, detailError{err: err, detail: "row " + strconv.Itoa(pos.Row)}
+			}
+		}
+		decoded := data.Bytes()
+		if compressor != nil {
+			if decoded, err = compressor.Decompress(decoded); err != nil {
+				return nil, detailError{err: SnapshotFormatError, detail: "row " + strconv.Itoa(pos.Row), cause: err}
+			}
+		}
+		buf.Grow((pos.Row - startRow + 1) * rowSize)
+		row, _ := buf.bufferPos(pos)
+		elements := bytes.NewReader(decoded)
+		for i := 0; i < int(n); i++ {
+			if buf.buffers[row][i], err = codec.Decode(elements); err != nil {
+				return nil, err
+			}
+		}
+		if elements.Len() != 0 {
+			return nil, detailError{err: SnapshotFormatError,
+				detail: strconv.Itoa(elements.Len()) + " trailing bytes in row " + strconv.Itoa(pos.Row)}
+		}
+		pos = pos.Move(int(n))
+	}
+	buf.Grow((write.Row - startRow + 1) * rowSize)
+	return buf, nil
+}
+
+// RestoreFile creates a new Buffer from a snapshot read from the file with the provided path (see RestoreFrom).
+func RestoreFile[T any](path string, codec Codec[T]) (*Buffer[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return RestoreFrom(f, codec)
+}
+
+// checkFrame reads the checksum of a snapshot frame and validates it against the frame content. If the checksum
+// doesn't match then SnapshotChecksumError is returned.
+func checkFrame(r io.Reader, n, size uint64, data []byte) error {
+	var sum [4]byte
+	if _, err := io.ReadFull(r, sum[:]); err != nil {
+		return snapshotError(err)
+	}
+	header := binary.AppendUvarint(binary.AppendUvarint(make([]byte, 0, 2*binary.MaxVarintLen64), n), size)
+	crc := crc32.Update(crc32.Checksum(header, snapshotTable), snapshotTable, data)
+	if crc != binary.LittleEndian.Uint32(sum[:]) {
+		return SnapshotChecksumError
+	}
+	return nil
+}
+
+// snapshotError converts an unexpected end of the snapshot into a SnapshotFormatError.
+func snapshotError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return detailError{err: SnapshotFormatError, detail: "truncated snapshot"}
+	}
+	return err
+}
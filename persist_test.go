@@ -0,0 +1,184 @@
+package gobuffer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestBufferSaveToRestoreFrom(t *testing.T) {
+	buf := NewWithSize[rune](3, 1)
+	for _, r := range "abcdefgh" {
+		buf.Write(r)
+	}
+	for i := 0; i < 4; i++ {
+		buf.Consume()
+	}
+	buf.Commit()
+	buf.Consume()
+	state := buf.State()
+	buf.Consume()
+	var snapshot bytes.Buffer
+	if err := buf.SaveTo(&snapshot, BinaryCodec[rune]{}); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	restored, err := RestoreFrom[rune](&snapshot, BinaryCodec[rune]{})
+	if err != nil {
+		t.Fatalf("unexpected restore error: %v", err)
+	}
+	if got := readAll(restored); got != "gh" {
+		t.Errorf("unexpected restored content:\nexp=%s\ngot=%s", "gh", got)
+	}
+	// States created before the snapshot are valid for the restored buffer
+	if err := restored.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	restored.Write('i')
+	if got := readAll(restored); got != "fghi" {
+		t.Errorf("unexpected restored content after rollback:\nexp=%s\ngot=%s", "fghi", got)
+	}
+}
+
+func TestBufferSaveFileRestoreFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "buffer.snapshot")
+	buf := New[int32]()
+	for i := int32(0); i < 25; i++ {
+		buf.Write(i)
+	}
+	buf.Consume()
+	if err := buf.SaveFile(path, BinaryCodec[int32]{}); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	restored, err := RestoreFile[int32](path, BinaryCodec[int32]{})
+	if err != nil {
+		t.Fatalf("unexpected restore error: %v", err)
+	}
+	if n := restored.Buffered(); n != 24 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 24, n)
+	}
+	if v, _ := restored.Next(); v != 1 {
+		t.Errorf("unexpected next:\nexp=%d\ngot=%d", 1, v)
+	}
+}
+
+func TestRestoreFrom_FormatError(t *testing.T) {
+	buf := New[rune]()
+	for _, r := range "abc" {
+		buf.Write(r)
+	}
+	var snapshot bytes.Buffer
+	if err := buf.SaveTo(&snapshot, BinaryCodec[rune]{}); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	data := snapshot.Bytes()
+	// A version 1 snapshot (without row checksums) holding a row with a wrapping length followed by a valid row
+	negativeRowLength := append([]byte(snapshotMagic), 1)
+	for _, v := range []uint64{4, 0, 0, 0, 1, 0, 1<<64 - 1, 0, 4, 16} {
+		negativeRowLength = binary.AppendUvarint(negativeRowLength, v)
+	}
+	negativeRowLength = append(negativeRowLength, make([]byte, 16)...)
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"bad magic", append([]byte("XOBUF"), data[5:]...)},
+		{"truncated", data[:len(data)-2]},
+		{"row out of range", snapshotHeader(4, 0, 0, 0, 1<<40, 0)},
+		{"row size out of range", snapshotHeader(1<<30, 0, 0, 0, 1, 0)},
+		{"elements out of range", snapshotHeader(1<<24, 0, 0, 0, 1<<20, 0)},
+		{"many rows without frames", snapshotHeader(4, 0, 0, 0, 1<<30, 0)},
+		{"frame size out of range", binary.AppendUvarint(snapshotHeader(4, 0, 0, 0, 1, 0), 1<<40)},
+		{"negative row length", negativeRowLength},
+		{"truncated frame", binary.AppendUvarint(binary.AppendUvarint(snapshotHeader(4, 0, 0, 0, 1, 0), 4), 1<<27)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := RestoreFrom[rune](bytes.NewReader(test.data), BinaryCodec[rune]{})
+			if !errors.Is(err, SnapshotFormatError) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", SnapshotFormatError, err)
+			}
+		})
+	}
+}
+
+// snapshotHeader returns a snapshot header (without frames) holding the provided geometry and positions.
+func snapshotHeader(values ...uint64) []byte {
+	header := append([]byte(snapshotMagic), snapshotVersion, 0)
+	for _, v := range values {
+		header = binary.AppendUvarint(header, v)
+	}
+	return header
+}
+
+func readAll(buf *Buffer[rune]) string {
+	var s []rune
+	for r, ok := buf.Next(); ok; r, ok = buf.Next() {
+		s = append(s, r)
+		buf.Consume()
+	}
+	return string(s)
+}
+
+func TestRestoreFrom_Checksum(t *testing.T) {
+	buf := NewWithSize[rune](4, 1)
+	for _, r := range "abc" {
+		buf.Write(r)
+	}
+	var snapshot bytes.Buffer
+	if err := buf.SaveTo(&snapshot, BinaryCodec[rune]{}); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	data := snapshot.Bytes()
+	corrupt := bytes.Clone(data)
+	corrupt[len(corrupt)-6] ^= 0x01
+	if _, err := RestoreFrom[rune](bytes.NewReader(corrupt), BinaryCodec[rune]{}); !errors.Is(err, SnapshotChecksumError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", SnapshotChecksumError, err)
+	}
+	// A corrupt row size is detected without trusting it
+	for bit := 0; bit < 8; bit++ {
+		corrupt = bytes.Clone(data)
+		corrupt[len(corrupt)-4-3*4-1] ^= 1 << bit
+		_, err := RestoreFrom[rune](bytes.NewReader(corrupt), BinaryCodec[rune]{})
+		if !errors.Is(err, SnapshotChecksumError) && !errors.Is(err, SnapshotFormatError) {
+			t.Errorf("unexpected error for bit %d:\nexp=%v\ngot=%v", bit, SnapshotChecksumError, err)
+		}
+	}
+	// A version 1 snapshot has no flags and no row checksums
+	v1 := append(bytes.Clone(data[:len(snapshotMagic)+1]), data[len(snapshotMagic)+2:len(data)-4]...)
+	v1[len(snapshotMagic)] = 1
+	restored, err := RestoreFrom[rune](bytes.NewReader(v1), BinaryCodec[rune]{})
+	if err != nil {
+		t.Fatalf("unexpected restore error: %v", err)
+	}
+	if got := readAll(restored); got != "abc" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "abc", got)
+	}
+}
+
+func TestRestoreFrom_Compressed(t *testing.T) {
+	buf := NewWithSize[rune](4, 1)
+	for _, r := range "hello world" {
+		buf.Write(r)
+	}
+	buf.Next()
+	buf.Consume()
+	codec := CompressedCodec[rune]{Codec: BinaryCodec[rune]{}, Compressor: GzipCompressor{}}
+	var snapshot bytes.Buffer
+	if err := buf.SaveTo(&snapshot, codec); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	restored, err := RestoreFrom[rune](bytes.NewReader(snapshot.Bytes()), codec)
+	if err != nil {
+		t.Fatalf("unexpected restore error: %v", err)
+	}
+	if got := readAll(restored); got != "ello world" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "ello world", got)
+	}
+	_, err = RestoreFrom[rune](bytes.NewReader(snapshot.Bytes()), BinaryCodec[rune]{})
+	if !errors.Is(err, SnapshotFormatError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", SnapshotFormatError, err)
+	}
+}
@@ -0,0 +1,112 @@
+package gobuffer
+
+import "context"
+
+// PipeWriter is the writing half of a pipe (see Pipe).
+type PipeWriter[T any] struct {
+	buf *SyncBuffer[T]
+}
+
+// PipeReader is the reading half of a pipe (see Pipe). The PipeReader provides the same lookahead (Next and
+// Consume) and rollback (State and Rollback) as a Buffer. Unlike a Buffer, PipeReader.Next blocks until an element
+// is written or the pipe is closed.
+type PipeReader[T any] struct {
+	buf *SyncBuffer[T]
+}
+
+var (
+	_ Writer[any] = (*PipeWriter[any])(nil)
+	_ View[any]   = (*PipeReader[any])(nil)
+	_ Source[any] = (*PipeReader[any])(nil)
+)
+
+// Pipe creates a pipe of elements (like io.Pipe). Elements written to the PipeWriter by one goroutine are read
+// from the PipeReader by another goroutine. The elements are buffered in a SyncBuffer. Hence, writes never block
+// and the reader may look ahead and roll back. When the writer closes the pipe (see PipeWriter.Close) the reader
+// gets io.EOF after the buffered elements have been read.
+func Pipe[T any]() (*PipeReader[T], *PipeWriter[T]) {
+	buf := NewSync[T]()
+	return &PipeReader[T]{buf: buf}, &PipeWriter[T]{buf: buf}
+}
+
+// Write writes an element to the pipe. If the pipe has been closed then the element is not written (see
+// PipeWriter.TryWrite).
+func (w *PipeWriter[T]) Write(element T) {
+	_ = w.TryWrite(element)
+}
+
+// TryWrite writes an element to the pipe. If the pipe has been closed then ClosedError is returned.
+func (w *PipeWriter[T]) TryWrite(element T) error {
+	return w.buf.TryWrite(element)
+}
+
+// Close closes the pipe. The reader gets io.EOF after the buffered elements have been read.
+func (w *PipeWriter[T]) Close() {
+	w.buf.Close()
+}
+
+// Next returns the next element from the pipe. Next blocks until an element is available. If the pipe has been
+// closed and all elements have been consumed then false is returned (and PipeReader.Err returns io.EOF).
+func (r *PipeReader[T]) Next() (element T, ok bool) {
+	if r.wait() != nil {
+		return
+	}
+	return r.buf.Next()
+}
+
+// Consume consumes the next element (returned by PipeReader.Next).
+func (r *PipeReader[T]) Consume() {
+	r.buf.Consume()
+}
+
+// Buffered returns the number of unconsumed elements in the pipe.
+func (r *PipeReader[T]) Buffered() int {
+	return r.buf.Buffered()
+}
+
+// State returns a state that may be used to roll back to the current read position (see Buffer.State).
+func (r *PipeReader[T]) State() State {
+	return r.buf.State()
+}
+
+// Rollback resets the read position to the provided state (see Buffer.Rollback).
+func (r *PipeReader[T]) Rollback(state State) error {
+	return r.buf.Rollback(state)
+}
+
+// Commit removes the consumed elements from the pipe (see Buffer.Commit).
+func (r *PipeReader[T]) Commit() {
+	r.buf.Commit()
+}
+
+// Err returns io.EOF if the pipe has been closed and all elements have been consumed. Otherwise, nil is returned.
+func (r *PipeReader[T]) Err() error {
+	return r.buf.Err()
+}
+
+// Read implements Source consuming up to len(p) elements into p. Read blocks until at least one element is
+// available. If the pipe has been closed and all elements have been consumed then io.EOF is returned.
+func (r *PipeReader[T]) Read(p []T) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err = r.wait(); err != nil {
+		return
+	}
+	r.buf.Do(func(buf *Buffer[T]) {
+		for chunk := range buf.readChunks() {
+			n += copy(p[n:], chunk)
+			if n == len(p) {
+				break
+			}
+		}
+		buf.consume(n)
+	})
+	return n, nil
+}
+
+// wait waits until at least one element is buffered. If the pipe has been closed and all elements have been
+// consumed then io.EOF is returned.
+func (r *PipeReader[T]) wait() error {
+	return r.buf.WaitUntil(context.Background(), func(buffered int) bool { return buffered > 0 })
+}
@@ -0,0 +1,49 @@
+package gobuffer
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestPipe(t *testing.T) {
+	r, w := Pipe[rune]()
+	go func() {
+		defer w.Close()
+		for _, r := range "if (x) y" {
+			w.Write(r)
+		}
+	}()
+	// Look ahead for the keyword and roll back
+	state := r.State()
+	var keyword []rune
+	for c, ok := r.Next(); ok && c != ' '; c, ok = r.Next() {
+		keyword = append(keyword, c)
+		r.Consume()
+	}
+	if string(keyword) != "if" {
+		t.Errorf("unexpected keyword:\nexp=%s\ngot=%s", "if", string(keyword))
+	}
+	if err := r.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	// The rest is read using the reader as a source
+	var got []rune
+	p := make([]rune, 3)
+	for {
+		n, err := r.Read(p)
+		got = append(got, p[:n]...)
+		if err != nil {
+			break
+		}
+	}
+	if string(got) != "if (x) y" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "if (x) y", string(got))
+	}
+	if err := r.Err(); !errors.Is(err, io.EOF) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", io.EOF, err)
+	}
+	if err := w.TryWrite('z'); !errors.Is(err, ClosedError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", ClosedError, err)
+	}
+}
@@ -0,0 +1,63 @@
+package gobuffer
+
+import "sync"
+
+// Pool is a pool of buffers with the same row size backed by a sync.Pool. A Pool is safe for concurrent use.
+type Pool[T any] struct {
+	pool    sync.Pool
+	rowSize int
+	maxRows int
+}
+
+// NewPool creates a new Pool of buffers with the specified row size and number of pre-allocated rows (see
+// NewWithSize). Buffers holding more than maxRows rows are not retained by the pool (see Pool.Put). If row size
+// or number of rows is <= 0, or if maxRows is less than the number of rows, then a panic is raised.
+func NewPool[T any](rowSize, rows, maxRows int) *Pool[T] {
+	if rowSize <= 0 {
+		panic(argumentError{"illegal non-positive row size", rowSize})
+	}
+	if rows <= 0 {
+		panic(argumentError{"illegal non-positive number of rows", rows})
+	}
+	if maxRows < rows {
+		panic(argumentError{"illegal max number of rows below number of rows", maxRows})
+	}
+	return &Pool[T]{
+		pool:    sync.Pool{New: func() any { return NewWithSize[T](rowSize, rows) }},
+		rowSize: rowSize,
+		maxRows: maxRows,
+	}
+}
+
+// Get returns an empty Buffer from the pool (or a new Buffer if the pool is empty).
+func (p *Pool[T]) Get() *Buffer[T] {
+	return p.pool.Get().(*Buffer[T])
+}
+
+// Put resets the Buffer and returns it to the pool. The Buffer must not be used after Put. The elements of the
+// Buffer are cleared and the configuration of the Buffer (like hooks, observers and the frozen state) is removed.
+// Unlike Buffer.Reset, no hooks are called for the removed elements.
+//
+// Buffers not suitable for reuse are not retained. That is, buffers holding more than the max number of rows,
+// buffers with another row size and buffers with a source or a row storage (see NewFromSource and
+// NewWithStorage).
+func (p *Pool[T]) Put(buf *Buffer[T]) {
+	if buf == nil || buf.rowSize != p.rowSize || len(buf.buffers) > p.maxRows || buf.source != nil ||
+		buf.storage != nil {
+		return
+	}
+	buf.unshare()
+	rows := buf.buffers
+	for _, row := range rows {
+		clear(row)
+	}
+	// The rows may reference the embedded row of a small Buffer which isn't touched (see NewWithSize)
+	*buf = Buffer[T]{
+		rowSize: buf.rowSize,
+		buffers: rows,
+		read:    position{rowSize: buf.rowSize},
+		write:   position{rowSize: buf.rowSize},
+		pinned:  -1,
+	}
+	p.pool.Put(buf)
+}
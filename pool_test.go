@@ -0,0 +1,42 @@
+package gobuffer
+
+import "testing"
+
+func TestPool(t *testing.T) {
+	pool := NewPool[rune](4, 1, 2)
+	buf := pool.Get()
+	buf.WithObserver(ObserverFunc(func(Event) {}))
+	for _, r := range "abcdef" {
+		buf.Write(r)
+	}
+	buf.Freeze()
+	pool.Put(buf)
+	// A returned Buffer is empty and has no configuration
+	if n := buf.TotalWritten(); n != 0 {
+		t.Errorf("unexpected written elements:\nexp=%d\ngot=%d", 0, n)
+	}
+	if buf.Frozen() || buf.observer != nil {
+		t.Errorf("unexpected configuration of returned buffer")
+	}
+	buf.Write('x')
+	if got := readAll(buf); got != "x" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "x", got)
+	}
+	// Buffers not suitable for reuse are not retained
+	tests := []struct {
+		name string
+		buf  *Buffer[rune]
+	}{
+		{"nil", nil},
+		{"too many rows", NewWithSize[rune](4, 3)},
+		{"other row size", NewWithSize[rune](8, 1)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.Put(test.buf)
+			if test.buf != nil && test.buf.TotalWritten() != 0 {
+				t.Errorf("unexpected reset of buffer")
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package gobuffer
+
+import "cmp"
+
+// Position is the position of an element in a Buffer (see Buffer.ReadPos and Buffer.WritePos). A Position is the
+// absolute offset of the element (counted from the first element ever written to the Buffer). Positions are
+// comparable and may be used as map keys. The line and column of a position in a text Buffer is provided by
+// Lines (see TrackLines).
+type Position struct {
+	offset int
+}
+
+// Offset returns the absolute offset of the position. The offset of the first element ever written to the Buffer
+// is 0.
+func (p Position) Offset() int {
+	return p.offset
+}
+
+// Add returns the position moved the specified number of elements (backward if n < 0). A position is never moved
+// before offset 0.
+func (p Position) Add(n int) Position {
+	return Position{max(p.offset+n, 0)}
+}
+
+// Compare compares the offsets of the positions. The result is -1 if p is before o, 0 if the positions are equal
+// and +1 if p is after o.
+func (p Position) Compare(o Position) int {
+	return cmp.Compare(p.offset, o.offset)
+}
+
+// ReadPos returns the position of the next element to read from the Buffer.
+func (b *Buffer[T]) ReadPos() Position {
+	return Position{b.read.AbsolutePos()}
+}
+
+// WritePos returns the position where the next element will be written to the Buffer.
+func (b *Buffer[T]) WritePos() Position {
+	return Position{b.write.AbsolutePos()}
+}
+
+// WriteState holds the write side state of a Buffer (see Buffer.WriteState). Unlike State a WriteState can't be
+// used to roll back the Buffer.
+type WriteState struct {
+	write position
+	init  bool
+}
+
+// Pos returns the write position held by the write state.
+func (s WriteState) Pos() Position {
+	return Position{s.write.AbsolutePos()}
+}
+
+// WriteState returns the current write side state of the Buffer (see Buffer.WrittenSince).
+func (b *Buffer[T]) WriteState() WriteState {
+	return WriteState{write: b.write, init: true}
+}
+
+// WrittenSince returns the number of elements written to the Buffer after the provided write state was created.
+// If elements have been removed by Buffer.Truncate since then the result may be negative. If the write state is
+// the "zero state" (not created by Buffer.WriteState) then a ZeroStateError is returned.
+func (b *Buffer[T]) WrittenSince(state WriteState) (int, error) {
+	if !state.init {
+		return 0, ZeroStateError
+	}
+	return b.write.AbsolutePos() - state.write.AbsolutePos(), nil
+}
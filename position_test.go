@@ -0,0 +1,115 @@
+package gobuffer
+
+import "testing"
+
+func TestBufferPosition(t *testing.T) {
+	buf := NewWithSize[rune](4, 1)
+	for _, r := range "abcdefghij" {
+		buf.Write(r)
+	}
+	for range 6 {
+		buf.Next()
+		buf.Consume()
+	}
+	buf.Commit()
+	read, write := buf.ReadPos(), buf.WritePos()
+	tests := []struct {
+		name   string
+		pos    Position
+		offset int
+	}{
+		{"read", read, 6},
+		{"write", write, 10},
+		{"add", read.Add(3), 9},
+		{"subtract", read.Add(-5), 1},
+		{"before start", read.Add(-10), 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.pos.Offset(); got != test.offset {
+				t.Errorf("unexpected offset:\nexp=%d\ngot=%d", test.offset, got)
+			}
+		})
+	}
+	if got := read.Compare(write); got != -1 {
+		t.Errorf("unexpected compare:\nexp=%d\ngot=%d", -1, got)
+	}
+	if got := write.Compare(read.Add(4)); got != 0 {
+		t.Errorf("unexpected compare:\nexp=%d\ngot=%d", 0, got)
+	}
+	if read.Add(4) != write {
+		t.Errorf("unexpected position:\nexp=%v\ngot=%v", write, read.Add(4))
+	}
+}
+
+func TestPosition_Comparable(t *testing.T) {
+	buf := NewWithSize[rune](4, 1)
+	for _, r := range "abcdef" {
+		buf.Write(r)
+	}
+	// Positions with the same offset are equal regardless of how they were created
+	if pos := (Position{}).Add(6); pos != buf.WritePos() {
+		t.Errorf("unexpected position:\nexp=%v\ngot=%v", buf.WritePos(), pos)
+	}
+}
+
+func TestTrackLines(t *testing.T) {
+	buf := NewWithSize[rune](4, 1)
+	buf.WriteSlice([]rune("package main\n\n"))
+	lines := TrackLines(buf)
+	buf.WriteSlice([]rune("func main() {\n\tx := 1\n}"))
+	tests := []struct {
+		offset    int
+		line, col int
+	}{
+		{0, 1, 1},
+		{12, 1, 13},
+		{13, 2, 1},
+		{14, 3, 1},
+		{28, 4, 1},
+		{29, 4, 2},
+		{36, 5, 1},
+	}
+	for _, test := range tests {
+		line, col, ok := lines.LineCol(Position{}.Add(test.offset))
+		if !ok || line != test.line || col != test.col {
+			t.Errorf("unexpected line and column of offset %d:\nexp=%d:%d\ngot=%d:%d (%v)", test.offset, test.line,
+				test.col, line, col, ok)
+		}
+	}
+	// Lines are not tracked after stop
+	lines.Stop()
+	buf.WriteSlice([]rune("\n\n"))
+	if line, _, _ := lines.LineCol(buf.WritePos()); line != 5 {
+		t.Errorf("unexpected line:\nexp=%d\ngot=%d", 5, line)
+	}
+	// Positions before the tracked text have no line
+	buf.ConsumeN(20)
+	buf.Commit()
+	if _, _, ok := TrackLines(buf).LineCol(Position{}); ok {
+		t.Errorf("unexpected line for position before the tracked lines")
+	}
+}
+
+func TestBufferWrittenSince(t *testing.T) {
+	buf := NewWithSize[rune](4, 1)
+	for _, r := range "abc" {
+		buf.Write(r)
+	}
+	state := buf.WriteState()
+	for _, r := range "defgh" {
+		buf.Write(r)
+	}
+	buf.Next()
+	buf.Consume()
+	buf.Commit()
+	if n, err := buf.WrittenSince(state); err != nil || n != 5 {
+		t.Errorf("unexpected written since:\nexp=%d, %v\ngot=%d, %v", 5, nil, n, err)
+	}
+	if got := state.Pos().Offset(); got != 3 {
+		t.Errorf("unexpected offset:\nexp=%d\ngot=%d", 3, got)
+	}
+	if _, err := buf.WrittenSince(WriteState{}); err != ZeroStateError {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", ZeroStateError, err)
+	}
+}
@@ -0,0 +1,93 @@
+package gobuffer
+
+import "iter"
+
+// CountFunc returns the number of unconsumed elements in the Buffer for which the predicate returns true. No
+// elements are consumed.
+func (b *Buffer[T]) CountFunc(pred func(T) bool) (n int) {
+	for chunk := range b.readChunks() {
+		for _, element := range chunk {
+			if pred(element) {
+				n++
+			}
+		}
+	}
+	return
+}
+
+// Count returns the number of unconsumed elements in the Buffer equal to v. No elements are consumed.
+func Count[T comparable](b *Buffer[T], v T) int {
+	return b.CountFunc(func(element T) bool { return element == v })
+}
+
+// ContainsFunc reports whether any unconsumed element in the Buffer satisfies the predicate. The search stops at
+// the first matching element. No elements are consumed.
+func (b *Buffer[T]) ContainsFunc(pred func(T) bool) bool {
+	for chunk := range b.readChunks() {
+		for _, element := range chunk {
+			if pred(element) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ToSlice returns a copy of the unconsumed elements in the Buffer. No elements are consumed.
+func (b *Buffer[T]) ToSlice() []T {
+	s := make([]T, 0, b.Buffered())
+	for chunk := range b.readChunks() {
+		s = append(s, chunk...)
+	}
+	return s
+}
+
+// SplitBuffered splits the unconsumed elements in the Buffer into segments separated by elements for which isSep
+// returns true. The separators are not included in the segments. Like strings.Split the last segment holds the
+// elements after the last separator (and may be empty). The segments are copies and no elements are consumed.
+func (b *Buffer[T]) SplitBuffered(isSep func(T) bool) [][]T {
+	segments := [][]T{{}}
+	for chunk := range b.readChunks() {
+		for _, element := range chunk {
+			if isSep(element) {
+				segments = append(segments, []T{})
+				continue
+			}
+			segments[len(segments)-1] = append(segments[len(segments)-1], element)
+		}
+	}
+	return segments
+}
+
+// ConsumeSplit returns an iterator over the complete segments of unconsumed elements in the Buffer. A complete
+// segment is terminated by an element for which isSep returns true. The separator is not included in the segment.
+// Each segment and its separator are consumed when the segment has been yielded. The elements after the last
+// separator (a trailing partial segment) are left unconsumed. The yielded segments are copies.
+func (b *Buffer[T]) ConsumeSplit(isSep func(T) bool) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		var segment []T
+		for {
+			n := 0
+			found := false
+			b.chunks(b.read, b.write, func(chunk []T) bool {
+				for _, element := range chunk {
+					n++
+					if isSep(element) {
+						found = true
+						return false
+					}
+					segment = append(segment, element)
+				}
+				return true
+			})
+			if !found {
+				return
+			}
+			b.consume(n)
+			if !yield(segment) {
+				return
+			}
+			segment = nil
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package gobuffer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBufferCount(t *testing.T) {
+	buf := NewWithSize[rune](3, 1)
+	for _, r := range "a\nb\nc\n\nd" {
+		buf.Write(r)
+	}
+	buf.Consume()
+	buf.Consume()
+	if n := Count(buf, '\n'); n != 3 {
+		t.Errorf("unexpected count:\nexp=%d\ngot=%d", 3, n)
+	}
+	if n := buf.CountFunc(func(r rune) bool { return r >= 'a' && r <= 'z' }); n != 3 {
+		t.Errorf("unexpected count func:\nexp=%d\ngot=%d", 3, n)
+	}
+	if n := buf.Buffered(); n != 6 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 6, n)
+	}
+}
+
+func TestBufferContainsFunc(t *testing.T) {
+	buf := NewWithSize[rune](3, 1)
+	for _, r := range "ab;cd" {
+		buf.Write(r)
+	}
+	isDelim := func(r rune) bool { return r == ';' }
+	if !buf.ContainsFunc(isDelim) {
+		t.Errorf("expected buffer to contain delimiter")
+	}
+	calls := 0
+	buf.ContainsFunc(func(r rune) bool {
+		calls++
+		return isDelim(r)
+	})
+	if calls != 3 {
+		t.Errorf("unexpected predicate calls:\nexp=%d\ngot=%d", 3, calls)
+	}
+	for i := 0; i < 3; i++ {
+		buf.Consume()
+	}
+	if buf.ContainsFunc(isDelim) {
+		t.Errorf("expected consumed delimiter not to be found")
+	}
+}
+
+func TestBufferSplitBuffered(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		exp   []string
+	}{
+		{"empty", "", []string{""}},
+		{"no separator", "abc", []string{"abc"}},
+		{"trailing partial", "ab,cde,f", []string{"ab", "cde", "f"}},
+		{"trailing separator", "ab,,c,", []string{"ab", "", "c", ""}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := NewWithSize[rune](2, 1)
+			for _, r := range test.input {
+				buf.Write(r)
+			}
+			var got []string
+			for _, segment := range buf.SplitBuffered(func(r rune) bool { return r == ',' }) {
+				got = append(got, string(segment))
+			}
+			if !slices.Equal(got, test.exp) {
+				t.Errorf("unexpected segments:\nexp=%q\ngot=%q", test.exp, got)
+			}
+			if n := buf.Buffered(); n != len(test.input) {
+				t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", len(test.input), n)
+			}
+		})
+	}
+}
+
+func TestBufferConsumeSplit(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "ab\n\ncde\nf" {
+		buf.Write(r)
+	}
+	isSep := func(r rune) bool { return r == '\n' }
+	var got []string
+	for segment := range buf.ConsumeSplit(isSep) {
+		got = append(got, string(segment))
+	}
+	exp := []string{"ab", "", "cde"}
+	if !slices.Equal(got, exp) {
+		t.Errorf("unexpected segments:\nexp=%q\ngot=%q", exp, got)
+	}
+	if got := readAll(buf); got != "f" {
+		t.Errorf("unexpected remaining:\nexp=%q\ngot=%q", "f", got)
+	}
+	// Stopping the iteration leaves the remaining segments unconsumed
+	for _, r := range "g\nh\n" {
+		buf.Write(r)
+	}
+	for range buf.ConsumeSplit(isSep) {
+		break
+	}
+	if got := readAll(buf); got != "h\n" {
+		t.Errorf("unexpected remaining:\nexp=%q\ngot=%q", "h\n", got)
+	}
+}
+
+func TestBufferToSlice(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abcde" {
+		buf.Write(r)
+	}
+	buf.Pop()
+	s := buf.ToSlice()
+	if string(s) != "bcde" {
+		t.Errorf("unexpected slice:\nexp=%s\ngot=%s", "bcde", string(s))
+	}
+	// The slice is a copy
+	s[0] = 'x'
+	if r, _ := buf.Next(); r != 'b' {
+		t.Errorf("unexpected next element:\nexp=%c\ngot=%c", 'b', r)
+	}
+}
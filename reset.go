@@ -0,0 +1,52 @@
+package gobuffer
+
+// Reset removes all elements from the Buffer and resets the read and write positions to the start. The allocated
+// rows are kept for reuse. Hence, a Buffer may be reused for many small jobs (e.g. parsing documents) without
+// allocating new buffers. The configuration of the Buffer (like hooks and observers) is kept. States, savepoints
+// and checkpoints created before the reset are no longer valid. Open cursors (see Buffer.NewCursor) are moved to
+// the start and read the elements written after the reset.
+//
+// The finalizer configured by Buffer.WithCommitFinalizer is called for all removed elements and the consumed hook
+// (see Buffer.OnConsumed) is called for all consumed elements not already passed to it. Resetting a frozen Buffer
+// is a misuse (see Buffer.Freeze).
+func (b *Buffer[T]) Reset() {
+	if !b.checkWritable("Reset") {
+		return
+	}
+	if b.onConsumed != nil {
+		b.release(b.read.AbsolutePos())
+	}
+	if b.commitFinalizer != nil {
+		b.chunks(b.startPos(), b.write, func(chunk []T) bool {
+			for _, element := range chunk {
+				b.commitFinalizer(element)
+			}
+			return true
+		})
+	}
+	b.unshare()
+	if b.storage != nil {
+		// The row numbers restart at 0 and the rows are allocated again
+		rows := len(b.buffers)
+		b.storage.TrimBefore(b.startRow + rows)
+		b.buffers = b.buffers[:0]
+		b.startRow = 0
+		b.Grow(rows * b.rowSize)
+	} else {
+		for _, row := range b.buffers {
+			clear(row)
+		}
+		b.startRow = 0
+	}
+	b.read = position{rowSize: b.rowSize}
+	b.write = position{rowSize: b.rowSize}
+	b.pinned = -1
+	b.released = 0
+	b.savepoints = nil
+	b.seqOffset = 0
+	b.seqMarks = nil
+	b.checkpoints = nil
+	for _, c := range b.cursors {
+		c.read, c.commit = b.read, b.read
+	}
+}
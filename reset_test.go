@@ -0,0 +1,69 @@
+package gobuffer
+
+import "testing"
+
+func TestBufferReset(t *testing.T) {
+	var finalized []rune
+	buf := NewWithSize[rune](2, 1).WithCommitFinalizer(func(r rune) { finalized = append(finalized, r) })
+	for _, r := range "abcdef" {
+		buf.Write(r)
+	}
+	buf.Pop()
+	buf.Pop()
+	buf.Pop()
+	buf.Commit()
+	buf.Reset()
+	if got := string(finalized); got != "abcdef" {
+		t.Errorf("unexpected finalized elements:\nexp=%s\ngot=%s", "abcdef", got)
+	}
+	if n := buf.TotalWritten(); n != 0 {
+		t.Errorf("unexpected written elements:\nexp=%d\ngot=%d", 0, n)
+	}
+	// The rows are reused
+	rows := len(buf.buffers)
+	allocs := testing.AllocsPerRun(10, func() {
+		for _, r := range "xy" {
+			buf.Write(r)
+		}
+		buf.Reset()
+	})
+	if allocs != 0 {
+		t.Errorf("unexpected allocations:\nexp=%v\ngot=%v", 0, allocs)
+	}
+	if n := len(buf.buffers); n != rows {
+		t.Errorf("unexpected number of rows:\nexp=%d\ngot=%d", rows, n)
+	}
+	buf.Write('z')
+	if got := readAll(buf); got != "z" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "z", got)
+	}
+}
+
+func TestBufferReset_Cursors(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abcdef" {
+		buf.Write(r)
+	}
+	c := buf.NewCursor()
+	for i := 0; i < 5; i++ {
+		c.Consume()
+	}
+	c.Commit()
+	buf.Reset()
+	// The cursor reads the elements written after the reset
+	if _, ok := c.Next(); ok {
+		t.Errorf("unexpected next element")
+	}
+	for _, r := range "xy" {
+		buf.Write(r)
+	}
+	if got := readCursor(c, 2); got != "xy" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "xy", got)
+	}
+	if err := buf.Join(c); err != nil {
+		t.Errorf("unexpected join error: %v", err)
+	}
+	if n := buf.Buffered(); n != 0 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 0, n)
+	}
+}
@@ -0,0 +1,25 @@
+package gobuffer
+
+import "slices"
+
+// ReshardInto creates a new Buffer with the specified row size holding the unconsumed elements of the Buffer. The
+// new Buffer is pre-allocated with (at least) the specified number of rows. The read and write positions of the
+// new Buffer have the same offsets as in the Buffer (see Buffer.ReadPos and Buffer.WritePos). Hence, TotalWritten
+// and TotalConsumed are the same for both buffers.
+//
+// The Buffer is not modified. Consumed elements, states and the Buffer configuration (like observers and
+// finalizers) are not carried over to the new Buffer. If row size or number of rows is <= 0 then a panic is
+// raised.
+func (b *Buffer[T]) ReshardInto(rowSize, rows int) *Buffer[T] {
+	buf := NewWithSize[T](rowSize, rows)
+	read := position{rowSize: rowSize}.Move(b.read.AbsolutePos())
+	buf.startRow = read.Row
+	buf.read, buf.write = read, read
+	buf.released = read.AbsolutePos()
+	buf.seqOffset, buf.seqMarks = b.seqOffset, slices.Clone(b.seqMarks)
+	b.chunks(b.read, b.write, func(chunk []T) bool {
+		buf.writeSlice(chunk)
+		return true
+	})
+	return buf
+}
@@ -0,0 +1,42 @@
+package gobuffer
+
+import "testing"
+
+func TestBufferReshardInto(t *testing.T) {
+	tests := []struct {
+		name    string
+		rowSize int
+		rows    int
+	}{
+		{"smaller rows", 2, 1},
+		{"bigger rows", 16, 1},
+		{"same rows", 4, 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := NewWithSize[rune](4, 1)
+			for _, r := range "abcdefghij" {
+				buf.Write(r)
+			}
+			for range 5 {
+				buf.Next()
+				buf.Consume()
+			}
+			buf.Commit()
+			resharded := buf.ReshardInto(test.rowSize, test.rows)
+			if got := resharded.ReadPos().Offset(); got != 5 {
+				t.Errorf("unexpected read offset:\nexp=%d\ngot=%d", 5, got)
+			}
+			if got := resharded.WritePos().Offset(); got != 10 {
+				t.Errorf("unexpected write offset:\nexp=%d\ngot=%d", 10, got)
+			}
+			resharded.Write('k')
+			if got := readAll(resharded); got != "fghijk" {
+				t.Errorf("unexpected resharded content:\nexp=%s\ngot=%s", "fghijk", got)
+			}
+			if got := readAll(buf); got != "fghij" {
+				t.Errorf("unexpected original content:\nexp=%s\ngot=%s", "fghij", got)
+			}
+		})
+	}
+}
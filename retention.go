@@ -0,0 +1,69 @@
+package gobuffer
+
+import "strconv"
+
+// RetentionReport describes the memory held by a Buffer broken down by the reason each row is retained. The
+// report is intended for diagnostics when the memory held by buffers grows unexpectedly.
+type RetentionReport struct {
+	RowSize int // RowSize holds the number of elements in each row.
+	Rows    int // Rows holds the total number of rows held by the Buffer.
+	// Committable holds the number of rows only holding consumed elements not needed for anything else. These rows
+	// are retained only because Buffer.Commit has not been called and are released by the next commit.
+	Committable int
+	// Retained holds the number of rows only holding consumed elements that Buffer.Commit doesn't release as they
+	// are held by the retained history, a live savepoint or an open cursor (see History, Savepoints and Cursors).
+	Retained int
+	// History, Savepoints and Cursors hold the number of rows before the row holding the read position held by
+	// the retained history (see Buffer.WithHistory), the live savepoints (see Buffer.Savepoint) and the open
+	// cursors (see Buffer.NewCursor) respectively. A row may be held for several reasons. Hence, the sum of the
+	// three may be larger than Retained.
+	History    int
+	Savepoints int
+	Cursors    int
+	// Live holds the number of rows from the row holding the read position up to the row holding the write
+	// position. These rows hold unconsumed elements (or will receive the next written element).
+	Live int
+	// Spare holds the number of rows pre-allocated after the row holding the write position.
+	Spare int
+}
+
+// Elements returns the number of elements held by the rows in the report.
+func (r RetentionReport) Elements() int {
+	return r.Rows * r.RowSize
+}
+
+func (r RetentionReport) String() string {
+	itoa := strconv.Itoa
+	return itoa(r.Rows) + " rows of " + itoa(r.RowSize) + " elements: " + itoa(r.Committable) + " committable, " +
+		itoa(r.Retained) + " retained (" + itoa(r.History) + " history, " + itoa(r.Savepoints) + " savepoints, " +
+		itoa(r.Cursors) + " cursors), " + itoa(r.Live) + " live, " + itoa(r.Spare) + " spare"
+}
+
+// RetentionReport returns a report describing why the memory held by the Buffer is retained.
+func (b *Buffer[T]) RetentionReport() RetentionReport {
+	readRow, _ := b.bufferPos(b.read)
+	writeRow, _ := b.bufferPos(b.write)
+	rows := len(b.buffers)
+	// The row holding the write position may not yet be allocated
+	live := min(writeRow+1, rows) - readRow
+	// held returns the number of rows before the row holding the read position held from the provided row
+	held := func(row int) int {
+		return min(max(b.read.Row-row, 0), readRow)
+	}
+	report := RetentionReport{
+		RowSize:  b.rowSize,
+		Rows:     rows,
+		Retained: held(b.retainRow()),
+		History:  held(max(b.read.Move(-b.history).Row, b.startRow)),
+		Live:     live,
+		Spare:    rows - readRow - live,
+	}
+	for _, s := range b.savepoints {
+		report.Savepoints = max(report.Savepoints, held(s.state.read.Row))
+	}
+	for _, c := range b.cursors {
+		report.Cursors = max(report.Cursors, held(c.commit.Row))
+	}
+	report.Committable = readRow - report.Retained
+	return report
+}
@@ -0,0 +1,66 @@
+package gobuffer
+
+import "testing"
+
+func TestBufferRetentionReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		writes  int
+		consume int
+		commit  bool
+		exp     RetentionReport
+	}{
+		{"empty", 0, 0, false, RetentionReport{RowSize: 4, Rows: 3, Live: 1, Spare: 2}},
+		{"partially written", 5, 0, false, RetentionReport{RowSize: 4, Rows: 3, Live: 2, Spare: 1}},
+		{"consumed", 9, 6, false, RetentionReport{RowSize: 4, Rows: 3, Committable: 1, Live: 2}},
+		{"committed", 9, 6, true, RetentionReport{RowSize: 4, Rows: 2, Live: 2}},
+		{"grown", 13, 0, false, RetentionReport{RowSize: 4, Rows: 4, Live: 4}},
+		{"row boundary", 12, 12, true, RetentionReport{RowSize: 4, Rows: 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := NewWithSize[int](4, 3)
+			for i := 0; i < test.writes; i++ {
+				buf.Write(i)
+			}
+			for i := 0; i < test.consume; i++ {
+				buf.Consume()
+			}
+			if test.commit {
+				buf.Commit()
+			}
+			if got := buf.RetentionReport(); got != test.exp {
+				t.Errorf("unexpected report:\nexp=%v\ngot=%v", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestBufferRetentionReport_Retained(t *testing.T) {
+	buf := NewWithSize[int](2, 1).WithHistory(3)
+	for i := 0; i < 12; i++ {
+		buf.Write(i)
+	}
+	buf.Consume()
+	c := buf.NewCursor()
+	buf.ConsumeN(2)
+	s := buf.Savepoint()
+	buf.ConsumeN(5)
+	exp := RetentionReport{RowSize: 2, Rows: 6, Committable: 0, Retained: 4, History: 2, Savepoints: 3, Cursors: 4,
+		Live: 2}
+	if got := buf.RetentionReport(); got != exp {
+		t.Errorf("unexpected report:\nexp=%v\ngot=%v", exp, got)
+	}
+	// Closing a cursor makes the rows only held by the cursor committable
+	c.Close()
+	exp = RetentionReport{RowSize: 2, Rows: 6, Committable: 1, Retained: 3, History: 2, Savepoints: 3, Live: 2}
+	if got := buf.RetentionReport(); got != exp {
+		t.Errorf("unexpected report:\nexp=%v\ngot=%v", exp, got)
+	}
+	// Releasing a savepoint releases the rows only held by the savepoint
+	s.Release()
+	exp = RetentionReport{RowSize: 2, Rows: 4, Retained: 2, History: 2, Live: 2}
+	if got := buf.RetentionReport(); got != exp {
+		t.Errorf("unexpected report:\nexp=%v\ngot=%v", exp, got)
+	}
+}
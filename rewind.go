@@ -0,0 +1,79 @@
+package gobuffer
+
+import (
+	"errors"
+	"io"
+)
+
+// RewindableReader is an io.Reader recording the bytes read from an underlying reader after a mark so that they
+// can be replayed (see RewindableReader.Mark and RewindableReader.Rewind).
+type RewindableReader struct {
+	r    io.Reader
+	buf  *ByteBuffer
+	mark State // mark holds the state to rewind to (zero state if not marked).
+}
+
+// NewRewindableReader creates a new RewindableReader reading from the provided reader.
+func NewRewindableReader(r io.Reader) *RewindableReader {
+	return &RewindableReader{
+		r:   r,
+		buf: NewByteBufferWithSize(512, 1),
+	}
+}
+
+// Read implements io.Reader. Bytes replayed after a rewind are returned before any new bytes are read from the
+// underlying reader. Bytes read after a mark are recorded until the next commit.
+func (r *RewindableReader) Read(p []byte) (int, error) {
+	if r.buf.Buffered() > 0 {
+		n, _ := r.buf.Read(p)
+		if !r.mark.init {
+			r.buf.Commit()
+		}
+		return n, nil
+	}
+	if !r.mark.init {
+		return r.r.Read(p)
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.buf.writeSlice(p[:n])
+		r.buf.consume(n)
+	}
+	return n, err
+}
+
+// Mark marks the current read position. Bytes read after the mark are recorded and may be replayed by calling
+// RewindableReader.Rewind. Calling Mark again moves the mark to the current read position.
+func (r *RewindableReader) Mark() {
+	r.mark = r.buf.State()
+}
+
+// Rewind resets the read position to the mark. The recorded bytes will be returned again by the following calls
+// to RewindableReader.Read. The mark is kept, so the reader may be rewound several times. If there is no mark
+// then ZeroStateError is returned.
+func (r *RewindableReader) Rewind() error {
+	return r.buf.Rollback(r.mark)
+}
+
+// Commit removes the mark and releases the recorded bytes already read. Recorded bytes not yet read after a
+// rewind are still returned by the following calls to RewindableReader.Read.
+func (r *RewindableReader) Commit() {
+	r.mark = State{}
+	r.buf.Commit()
+}
+
+// Sniff reads up to n bytes from the provided reader and returns them together with a RewindableReader replaying
+// the sniffed bytes before continuing reading from the provided reader. An end of the reader before n bytes have
+// been read is not treated as an error.
+func Sniff(reader io.Reader, n int) ([]byte, *RewindableReader, error) {
+	r := NewRewindableReader(reader)
+	r.Mark()
+	prefix := make([]byte, n)
+	read, err := io.ReadFull(r, prefix)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil
+	}
+	_ = r.Rewind()
+	r.Commit()
+	return prefix[:read], r, err
+}
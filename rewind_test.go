@@ -0,0 +1,67 @@
+package gobuffer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestRewindableReader(t *testing.T) {
+	r := NewRewindableReader(iotest.OneByteReader(strings.NewReader("GIF89a...image data")))
+	r.Mark()
+	magic := make([]byte, 6)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if string(magic) != "GIF89a" {
+		t.Errorf("unexpected magic:\nexp=%s\ngot=%s", "GIF89a", magic)
+	}
+	if err := r.Rewind(); err != nil {
+		t.Fatalf("unexpected rewind error: %v", err)
+	}
+	// Rewinding twice replays the same bytes
+	if _, err := io.ReadFull(r, magic[:3]); err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if err := r.Rewind(); err != nil {
+		t.Fatalf("unexpected rewind error: %v", err)
+	}
+	r.Commit()
+	all, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if string(all) != "GIF89a...image data" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "GIF89a...image data", all)
+	}
+	if err := r.Rewind(); !errors.Is(err, ZeroStateError) {
+		t.Errorf("unexpected rewind error:\nexp=%v\ngot=%v", ZeroStateError, err)
+	}
+}
+
+func TestRewindableReader_ReadsPassThrough(t *testing.T) {
+	err := iotest.TestReader(NewRewindableReader(strings.NewReader("plain content")), []byte("plain content"))
+	if err != nil {
+		t.Errorf("unexpected reader error: %v", err)
+	}
+}
+
+func TestSniff(t *testing.T) {
+	prefix, r, err := Sniff(strings.NewReader("%PDF-1.7 document"), 5)
+	if err != nil {
+		t.Fatalf("unexpected sniff error: %v", err)
+	}
+	if string(prefix) != "%PDF-" {
+		t.Errorf("unexpected prefix:\nexp=%s\ngot=%s", "%PDF-", prefix)
+	}
+	all, _ := io.ReadAll(r)
+	if string(all) != "%PDF-1.7 document" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "%PDF-1.7 document", all)
+	}
+	// A reader ending before n bytes is not an error
+	if prefix, _, err = Sniff(strings.NewReader("GE"), 4); err != nil || string(prefix) != "GE" {
+		t.Errorf("unexpected sniff result: %q, %v", prefix, err)
+	}
+}
@@ -0,0 +1,110 @@
+package gobuffer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// RuneSource is a Source of runes decoded from UTF-8 encoded text read from an io.Reader. Invalid encodings are
+// decoded as utf8.RuneError. Optionally, a leading byte order mark (BOM) is detected and stripped (see
+// RuneSource.StripBOM).
+type RuneSource struct {
+	r        *bufio.Reader
+	stripBOM bool
+	started  bool             // started is true when the first rune has been read.
+	utf16    binary.ByteOrder // utf16 holds the byte order of UTF-16 encoded text (nil if UTF-8).
+	err      error            // err holds the error returned by the reader (io.EOF at the end).
+}
+
+// NewRuneSource creates a new RuneSource decoding the text read from the provided reader.
+func NewRuneSource(r io.Reader) *RuneSource {
+	return &RuneSource{r: bufio.NewReader(r)}
+}
+
+// NewFromReader creates a new rune Buffer lazily decoding the UTF-8 encoded text read from the provided reader
+// (see NewRuneSource and NewFromSource). The reader is only read when Buffer.Next runs out of buffered runes. Use
+// Buffer.Err to find out if reading the text failed.
+func NewFromReader(r io.Reader) *Buffer[rune] {
+	return NewFromSource[rune](NewRuneSource(r))
+}
+
+// StripBOM makes the RuneSource detect and strip a byte order mark (BOM) at the start of the text. A UTF-8 BOM
+// is just stripped. A UTF-16 BOM (big or little endian) is stripped and the following text is transcoded from
+// UTF-16. Hence, the BOM is never exposed as the first element of a Buffer and positions are counted from the
+// first rune of the text. The RuneSource is returned.
+//
+// StripBOM must be called before the first rune is read. Without StripBOM a BOM is returned as the rune U+FEFF.
+func (s *RuneSource) StripBOM() *RuneSource {
+	s.stripBOM = true
+	return s
+}
+
+// Read reads up to len(p) runes into p. Read only blocks on the underlying reader if no rune has been read. At
+// the end of the text io.EOF is returned.
+func (s *RuneSource) Read(p []rune) (n int, err error) {
+	if !s.started {
+		s.started = true
+		if s.stripBOM {
+			s.detectBOM()
+		}
+	}
+	for n < len(p) && s.err == nil && (n == 0 || s.r.Buffered() >= utf8.UTFMax) {
+		var r rune
+		if s.utf16 != nil {
+			r, s.err = s.readUTF16()
+		} else {
+			r, _, s.err = s.r.ReadRune()
+		}
+		if s.err == nil {
+			p[n] = r
+			n++
+		}
+	}
+	if n > 0 {
+		return n, nil
+	}
+	return 0, s.err
+}
+
+// detectBOM detects and discards a byte order mark at the start of the text.
+func (s *RuneSource) detectBOM() {
+	bom, _ := s.r.Peek(3)
+	switch {
+	case len(bom) >= 3 && bom[0] == 0xEF && bom[1] == 0xBB && bom[2] == 0xBF:
+		_, _ = s.r.Discard(3)
+	case len(bom) >= 2 && bom[0] == 0xFE && bom[1] == 0xFF:
+		s.utf16 = binary.BigEndian
+		_, _ = s.r.Discard(2)
+	case len(bom) >= 2 && bom[0] == 0xFF && bom[1] == 0xFE:
+		s.utf16 = binary.LittleEndian
+		_, _ = s.r.Discard(2)
+	}
+}
+
+// readUTF16 reads a UTF-16 encoded rune. Unpaired surrogates and a trailing odd byte are decoded as
+// utf8.RuneError.
+func (s *RuneSource) readUTF16() (rune, error) {
+	unit, err := s.r.Peek(2)
+	if len(unit) < 2 {
+		if len(unit) == 1 {
+			_, _ = s.r.Discard(1)
+			return utf8.RuneError, nil
+		}
+		return 0, err
+	}
+	r1 := rune(s.utf16.Uint16(unit))
+	_, _ = s.r.Discard(2)
+	if !utf16.IsSurrogate(r1) {
+		return r1, nil
+	}
+	if unit, _ = s.r.Peek(2); len(unit) == 2 {
+		if r := utf16.DecodeRune(r1, rune(s.utf16.Uint16(unit))); r != utf8.RuneError {
+			_, _ = s.r.Discard(2)
+			return r, nil
+		}
+	}
+	return utf8.RuneError, nil
+}
@@ -0,0 +1,60 @@
+package gobuffer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestRuneSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		stripBOM bool
+		exp      string
+	}{
+		{"plain", "let x = 'ä';", false, "let x = 'ä';"},
+		{"UTF-8 BOM kept", "\xEF\xBB\xBFab", false, "\uFEFFab"},
+		{"UTF-8 BOM stripped", "\xEF\xBB\xBFab", true, "ab"},
+		{"no BOM to strip", "ab", true, "ab"},
+		{"UTF-16 big endian", "\xFE\xFF\x00a\x00\xE4\xD8\x3D\xDE\x00", true, "aä😀"},
+		{"UTF-16 little endian", "\xFF\xFEa\x00\xE4\x00\x3D\xD8\x00\xDE", true, "aä😀"},
+		{"UTF-16 unpaired surrogate", "\xFE\xFF\xD8\x3D\x00a\x00", true, "\uFFFDa\uFFFD"},
+		{"invalid UTF-8", "a\xFFb", false, "a\uFFFDb"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := NewRuneSource(iotest.HalfReader(strings.NewReader(test.input)))
+			if test.stripBOM {
+				src.StripBOM()
+			}
+			if got := readAll(NewFromSourceWithSize[rune](src, 2, 1)); got != test.exp {
+				t.Errorf("unexpected runes:\nexp=%q\ngot=%q", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestRuneSource_ReaderError(t *testing.T) {
+	failure := errors.New("connection reset")
+	buf := NewFromSource[rune](NewRuneSource(io.MultiReader(strings.NewReader("ab"), iotest.ErrReader(failure))))
+	if got := readAll(buf); got != "ab" {
+		t.Errorf("unexpected runes:\nexp=%q\ngot=%q", "ab", got)
+	}
+	if err := buf.Err(); !errors.Is(err, failure) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", failure, err)
+	}
+}
+
+func TestNewFromReader(t *testing.T) {
+	text := "π ≈ 3.14159\n"
+	buf := NewFromReader(iotest.HalfReader(strings.NewReader(text)))
+	if got := readAll(buf); got != text {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", text, got)
+	}
+	if err := buf.Err(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package gobuffer
+
+import "slices"
+
+// Savepoint is a saved read state of a Buffer tying the retention of Buffer rows to its lifetime (see
+// Buffer.Savepoint).
+type Savepoint[T any] struct {
+	buf   *Buffer[T]
+	state State
+}
+
+// Savepoint creates a savepoint holding the current read state of the Buffer. As long as the savepoint is live
+// (not released) Buffer.Commit doesn't remove the row holding the savepoint position. Hence, a rollback to a live
+// savepoint only fails if the savepoint position has been discarded (see Buffer.Truncate and Buffer.ConsumeBack)
+// after a rollback to an earlier position. When the savepoint is released (see Savepoint.Release) and it was the
+// oldest live savepoint then the rows no longer needed are removed immediately as if Buffer.Commit was called.
+func (b *Buffer[T]) Savepoint() *Savepoint[T] {
+	s := &Savepoint[T]{buf: b, state: b.State()}
+	b.savepoints = append(b.savepoints, s)
+	return s
+}
+
+// State returns the Buffer state held by the savepoint.
+func (s *Savepoint[T]) State() State {
+	return s.state
+}
+
+// Rollback resets the Buffer read state to the savepoint. The savepoint is still live after the rollback. If the
+// savepoint has been released or its position has been discarded (see Buffer.Savepoint) then an
+// IllegalStateError is returned (see Buffer.Rollback).
+func (s *Savepoint[T]) Rollback() error {
+	return s.buf.Rollback(s.state)
+}
+
+// Release releases the savepoint. If the savepoint was the oldest live savepoint then the Buffer rows only
+// retained by the savepoint are removed immediately (see Buffer.Commit). Releasing a savepoint more than once has
+// no effect.
+func (s *Savepoint[T]) Release() {
+	b := s.buf
+	i := slices.Index(b.savepoints, s)
+	if i < 0 {
+		return
+	}
+	before := b.retainRow()
+	b.savepoints = slices.Delete(b.savepoints, i, i+1)
+	if after := b.retainRow(); after > before {
+		b.trim(after)
+	}
+}
@@ -0,0 +1,71 @@
+package gobuffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBufferSavepoint(t *testing.T) {
+	buf := NewWithSize[int](2, 1)
+	for i := 0; i < 10; i++ {
+		buf.Write(i)
+	}
+	buf.Consume()
+	outer := buf.Savepoint()
+	for i := 0; i < 3; i++ {
+		buf.Consume()
+	}
+	inner := buf.Savepoint()
+	for i := 0; i < 4; i++ {
+		buf.Consume()
+	}
+	// Commit keeps the rows of the live savepoints
+	buf.Commit()
+	if rows := buf.RetentionReport().Rows; rows != 5 {
+		t.Errorf("unexpected rows:\nexp=%d\ngot=%d", 5, rows)
+	}
+	if err := inner.Rollback(); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	if next, _ := buf.Next(); next != 4 {
+		t.Errorf("unexpected next:\nexp=%d\ngot=%d", 4, next)
+	}
+	for i := 0; i < 4; i++ {
+		buf.Consume()
+	}
+	// Releasing the inner savepoint doesn't release any rows as the outer savepoint is older
+	inner.Release()
+	if rows := buf.RetentionReport().Rows; rows != 5 {
+		t.Errorf("unexpected rows:\nexp=%d\ngot=%d", 5, rows)
+	}
+	// Releasing the outer savepoint trims the rows up to the read position
+	outer.Release()
+	outer.Release()
+	if rows := buf.RetentionReport().Rows; rows != 1 {
+		t.Errorf("unexpected rows:\nexp=%d\ngot=%d", 1, rows)
+	}
+	if next, _ := buf.Next(); next != 8 {
+		t.Errorf("unexpected next:\nexp=%d\ngot=%d", 8, next)
+	}
+}
+
+func TestBufferSavepoint_Discarded(t *testing.T) {
+	buf := NewWithSize[int](2, 1)
+	for i := 0; i < 3; i++ {
+		buf.Write(i)
+	}
+	state := buf.State()
+	buf.ConsumeN(3)
+	sp := buf.Savepoint()
+	if err := buf.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	// The savepoint position is discarded although the savepoint is live
+	buf.ConsumeBack()
+	if err := sp.Rollback(); !errors.Is(err, IllegalStateError) {
+		t.Errorf("unexpected rollback error:\nexp=%v\ngot=%v", IllegalStateError, err)
+	}
+	if next, _ := buf.Next(); next != 0 {
+		t.Errorf("unexpected next:\nexp=%d\ngot=%d", 0, next)
+	}
+}
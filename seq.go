@@ -0,0 +1,64 @@
+package gobuffer
+
+import "slices"
+
+// seqMark tells that the sequence number of the element at position pos (and the following elements up to the
+// next mark) is the position plus shift (see Buffer.NextSeq).
+type seqMark struct {
+	pos   int
+	shift int
+}
+
+// seq returns the sequence number of the element at the specified absolute position.
+func (b *Buffer[T]) seq(pos int) int {
+	shift := 0
+	for _, m := range b.seqMarks {
+		if m.pos > pos {
+			break
+		}
+		shift = m.shift
+	}
+	return pos + shift
+}
+
+// setSeqMark sets the shift of the sequence numbers from the specified absolute position (see seqMark).
+func (b *Buffer[T]) setSeqMark(pos, shift int) {
+	i, found := slices.BinarySearchFunc(b.seqMarks, pos, func(m seqMark, pos int) int { return m.pos - pos })
+	if found {
+		b.seqMarks[i].shift = shift
+		return
+	}
+	b.seqMarks = slices.Insert(b.seqMarks, i, seqMark{pos: pos, shift: shift})
+}
+
+// discardedBack records that n elements have been discarded from the back of the Buffer (the write position has
+// been moved n elements backward). The elements written later get new sequence numbers.
+func (b *Buffer[T]) discardedBack(n int) {
+	write := b.write.AbsolutePos()
+	i, _ := slices.BinarySearchFunc(b.seqMarks, write, func(m seqMark, pos int) int { return m.pos - pos })
+	b.seqMarks = b.seqMarks[:i]
+	b.seqOffset += n
+	b.setSeqMark(write, b.seqOffset)
+}
+
+// writtenFront records that an element has been written to the front of the Buffer at the specified absolute
+// position (see Buffer.WriteFront). The element gets a new sequence number.
+func (b *Buffer[T]) writtenFront(pos int) {
+	write := b.write.AbsolutePos()
+	if next := pos + 1; next < write {
+		b.setSeqMark(next, b.seq(next)-next)
+	}
+	b.setSeqMark(pos, int(b.TotalWritten())-pos)
+	b.seqOffset++
+	b.setSeqMark(write, b.seqOffset)
+}
+
+// trimSeqMarks removes the marks no longer needed for the elements still available in the Buffer.
+func (b *Buffer[T]) trimSeqMarks() {
+	start := b.startPos().AbsolutePos()
+	i := 0
+	for i+1 < len(b.seqMarks) && b.seqMarks[i+1].pos <= start {
+		i++
+	}
+	b.seqMarks = b.seqMarks[i:]
+}
@@ -0,0 +1,51 @@
+package gobuffer
+
+// Sharded is a buffer partitioning written elements across a number of shards by a user provided key function.
+// Each shard is a SyncBuffer that may be consumed independently of the other shards.
+//
+// All elements with the same key are written to the same shard. As each shard is a FIFO buffer the order of
+// elements with the same key is preserved.
+type Sharded[T any] struct {
+	shards []*SyncBuffer[T]
+	key    func(T) uint64
+}
+
+// NewSharded creates a new Sharded buffer with n shards. Elements are written to the shard with index key(e) % n.
+// If n is <= 0 then a panic is raised.
+func NewSharded[T any](n int, key func(T) uint64) *Sharded[T] {
+	if n <= 0 {
+		panic(argumentError{"illegal non-positive number of shards", n})
+	}
+	shards := make([]*SyncBuffer[T], n)
+	for i := range shards {
+		shards[i] = NewSync[T]()
+	}
+	return &Sharded[T]{
+		shards: shards,
+		key:    key,
+	}
+}
+
+// Write writes an element to the shard selected by the key of the element. Write is safe for concurrent use.
+func (s *Sharded[T]) Write(element T) {
+	s.shards[s.key(element)%uint64(len(s.shards))].Write(element)
+}
+
+// Shards returns the number of shards.
+func (s *Sharded[T]) Shards() int {
+	return len(s.shards)
+}
+
+// Shard returns the shard with the provided index. If the index is not in [0, Sharded.Shards) then a panic is
+// raised.
+func (s *Sharded[T]) Shard(i int) *SyncBuffer[T] {
+	return s.shards[i]
+}
+
+// Buffered returns the total number of unconsumed elements in all shards.
+func (s *Sharded[T]) Buffered() (n int) {
+	for _, shard := range s.shards {
+		n += shard.Buffered()
+	}
+	return
+}
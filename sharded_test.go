@@ -0,0 +1,52 @@
+package gobuffer
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSharded(t *testing.T) {
+	type event struct {
+		key uint64
+		seq int
+	}
+	buf := NewSharded(3, func(e event) uint64 { return e.key })
+	for i := 0; i < 100; i++ {
+		buf.Write(event{key: uint64(i % 7), seq: i})
+	}
+	if n := buf.Buffered(); n != 100 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 100, n)
+	}
+	var wg sync.WaitGroup
+	counts := make([]int, buf.Shards())
+	for i := 0; i < buf.Shards(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shard := buf.Shard(i)
+			last := map[uint64]int{}
+			for e, ok := shard.Next(); ok; e, ok = shard.Next() {
+				if e.key%3 != uint64(i) {
+					t.Errorf("unexpected key %d in shard %d", e.key, i)
+				}
+				if prev, found := last[e.key]; found && prev > e.seq {
+					t.Errorf("unexpected order for key %d: %d after %d", e.key, e.seq, prev)
+				}
+				last[e.key] = e.seq
+				shard.Consume()
+				counts[i]++
+			}
+		}()
+	}
+	wg.Wait()
+	if total := counts[0] + counts[1] + counts[2]; total != 100 {
+		t.Errorf("unexpected consumed:\nexp=%d\ngot=%d", 100, total)
+	}
+}
+
+func TestNewSharded_NonPositivePanic(t *testing.T) {
+	defer func() { _ = recover() }()
+
+	_ = NewSharded(0, func(i int) uint64 { return uint64(i) })
+	t.Errorf("expected NewSharded to panic")
+}
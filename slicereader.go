@@ -0,0 +1,74 @@
+package gobuffer
+
+import "io"
+
+// SliceReader is a read-only reader over the elements of a slice. A SliceReader provides the same lookahead
+// (Reader) and rollback (Rollbacker) as a Buffer without the row machinery.
+//
+// A SliceReader is also a Source (see SliceReader.Read) and may therefore feed a Buffer (see NewFromSource).
+type SliceReader[T any] struct {
+	elements []T
+	read     int // read holds the index of the next element to read.
+}
+
+var (
+	_ View[any]   = (*SliceReader[any])(nil)
+	_ Source[any] = (*SliceReader[any])(nil)
+)
+
+// NewSliceReader creates a new SliceReader reading the elements of the provided slice. The slice is not copied
+// and must not be modified while read.
+func NewSliceReader[T any](elements []T) *SliceReader[T] {
+	return &SliceReader[T]{elements: elements}
+}
+
+// Next returns the next element. If all elements have been consumed then false is returned.
+func (r *SliceReader[T]) Next() (element T, ok bool) {
+	if r.read >= len(r.elements) {
+		return
+	}
+	return r.elements[r.read], true
+}
+
+// Consume consumes the next element (returned by SliceReader.Next).
+func (r *SliceReader[T]) Consume() {
+	if r.read < len(r.elements) {
+		r.read++
+	}
+}
+
+// Buffered returns the number of unconsumed elements.
+func (r *SliceReader[T]) Buffered() int {
+	return len(r.elements) - r.read
+}
+
+// State returns a state that may be used to roll back to the current state.
+func (r *SliceReader[T]) State() State {
+	// The positions of a SliceReader have no rows. The row size 0 tells them apart from Buffer positions.
+	return newState(position{Col: r.read}, position{Col: len(r.elements)})
+}
+
+// Rollback resets the read state to the provided state. If the provided state is the "zero state" then a
+// ZeroStateError is returned. If the provided state wasn't created by a SliceReader over the same slice (or at
+// least one of the same length) then an IllegalStateError is returned.
+func (r *SliceReader[T]) Rollback(state State) error {
+	if !state.init {
+		return ZeroStateError
+	}
+	if state.read.rowSize != 0 || state.write.Col != len(r.elements) {
+		return IllegalStateError
+	}
+	r.read = state.read.Col
+	return nil
+}
+
+// Read implements Source consuming up to len(p) elements into p. When all elements have been consumed io.EOF is
+// returned.
+func (r *SliceReader[T]) Read(p []T) (int, error) {
+	if r.read >= len(r.elements) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.elements[r.read:])
+	r.read += n
+	return n, nil
+}
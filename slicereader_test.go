@@ -0,0 +1,59 @@
+package gobuffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSliceReader(t *testing.T) {
+	r := NewSliceReader([]rune("let x"))
+	if n := r.Buffered(); n != 5 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 5, n)
+	}
+	r.Consume()
+	state := r.State()
+	if got := readView(r); got != "et x" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "et x", got)
+	}
+	r.Consume()
+	if err := r.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	// A SliceReader may be used where a View is expected
+	view := FilterView[rune](NewFromSource[rune](r), func(r rune) bool { return r != ' ' })
+	if got := readView(view); got != "etx" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "etx", got)
+	}
+}
+
+func TestSliceReader_RollbackErrors(t *testing.T) {
+	r := NewSliceReader([]int{1, 2, 3})
+	buf := NewWithSize[int](1, 1)
+	buf.Write(1)
+	buf.Write(2)
+	buf.Write(3)
+	tests := []struct {
+		name  string
+		state State
+		err   error
+	}{
+		{"zero state", State{}, ZeroStateError},
+		{"buffer state", buf.State(), IllegalStateError},
+		{"other slice", NewSliceReader([]int{1}).State(), IllegalStateError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := r.Rollback(test.state); !errors.Is(err, test.err) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestBufferRollback_SliceReaderState(t *testing.T) {
+	buf := New[int]()
+	buf.Write(1)
+	if err := buf.Rollback(NewSliceReader([]int{1}).State()); !errors.Is(err, IllegalStateError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", IllegalStateError, err)
+	}
+}
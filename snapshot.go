@@ -0,0 +1,79 @@
+package gobuffer
+
+import (
+	"iter"
+	"slices"
+)
+
+// ReadOnlySnapshot is an immutable copy of the unconsumed elements of a Buffer at the time the snapshot was taken
+// (see Buffer.Snapshot). A snapshot may be read from any goroutine without coordinating with the Buffer.
+type ReadOnlySnapshot[T any] struct {
+	rows    [][]T // rows holds the elements. All rows except the first and the last are complete.
+	rowSize int
+	length  int
+}
+
+// Snapshot returns an immutable snapshot of the unconsumed elements in the Buffer. No elements are consumed.
+//
+// Complete Buffer rows are shared copy-on-write between the Buffer and the snapshot. That is, a shared row is
+// copied by the Buffer before it is modified in place (e.g. by Buffer.Apply). Only the row holding the write
+// position is copied when the snapshot is taken.
+func (b *Buffer[T]) Snapshot() ReadOnlySnapshot[T] {
+	snapshot := ReadOnlySnapshot[T]{rowSize: b.rowSize, length: b.Buffered()}
+	if snapshot.length == 0 {
+		return snapshot
+	}
+	b.chunks(b.read, b.write, func(chunk []T) bool {
+		snapshot.rows = append(snapshot.rows, chunk)
+		return true
+	})
+	// The row holding the write position is still written to and is therefore copied
+	if b.write.Col > 0 {
+		last := len(snapshot.rows) - 1
+		snapshot.rows[last] = slices.Clone(snapshot.rows[last])
+	}
+	b.shared = max(b.shared, b.write.Row)
+	return snapshot
+}
+
+// unshare copies all Buffer rows shared with snapshots (see Buffer.Snapshot). It must be called before any
+// buffered element is modified in place.
+func (b *Buffer[T]) unshare() {
+	for row := b.startRow; row < b.shared && row-b.startRow < len(b.buffers); row++ {
+		b.buffers[row-b.startRow] = slices.Clone(b.buffers[row-b.startRow])
+		if b.storage != nil {
+			b.storage.PutRow(row, b.buffers[row-b.startRow])
+		}
+	}
+	b.shared = 0
+}
+
+// Len returns the number of elements in the snapshot.
+func (s ReadOnlySnapshot[T]) Len() int {
+	return s.length
+}
+
+// At returns the i-th element in the snapshot. If i is out of range then a panic is raised.
+func (s ReadOnlySnapshot[T]) At(i int) T {
+	if i < 0 || i >= s.length {
+		panic(argumentError{"snapshot index out of range", i})
+	}
+	if i < len(s.rows[0]) {
+		return s.rows[0][i]
+	}
+	i -= len(s.rows[0])
+	return s.rows[1+i/s.rowSize][i%s.rowSize]
+}
+
+// All returns an iterator over the elements in the snapshot.
+func (s ReadOnlySnapshot[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, row := range s.rows {
+			for _, element := range row {
+				if !yield(element) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package gobuffer
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestBufferSnapshot(t *testing.T) {
+	buf := NewWithSize[rune](3, 1)
+	for _, r := range "-abcdefg" {
+		buf.Write(r)
+	}
+	buf.Consume()
+	snapshot := buf.Snapshot()
+	// Modify the buffer in all possible ways after the snapshot was taken
+	buf.Apply(func(r *rune) { *r = unicode.ToUpper(*r) })
+	buf.Truncate(5)
+	for _, r := range "xyz" {
+		buf.Write(r)
+	}
+	buf.Consume()
+	buf.Commit()
+	if got := readAll(buf); got != "BCDExyz" {
+		t.Errorf("unexpected buffer content:\nexp=%s\ngot=%s", "BCDExyz", got)
+	}
+	if n := snapshot.Len(); n != 7 {
+		t.Errorf("unexpected snapshot length:\nexp=%d\ngot=%d", 7, n)
+	}
+	var got []rune
+	for r := range snapshot.All() {
+		got = append(got, r)
+	}
+	if string(got) != "abcdefg" {
+		t.Errorf("unexpected snapshot content:\nexp=%s\ngot=%s", "abcdefg", string(got))
+	}
+	got = got[:0]
+	for i := 0; i < snapshot.Len(); i++ {
+		got = append(got, snapshot.At(i))
+	}
+	if string(got) != "abcdefg" {
+		t.Errorf("unexpected snapshot content by index:\nexp=%s\ngot=%s", "abcdefg", string(got))
+	}
+}
+
+func TestBufferSnapshot_Empty(t *testing.T) {
+	snapshot := New[rune]().Snapshot()
+	if n := snapshot.Len(); n != 0 {
+		t.Errorf("unexpected snapshot length:\nexp=%d\ngot=%d", 0, n)
+	}
+	for range snapshot.All() {
+		t.Errorf("unexpected snapshot element")
+	}
+}
+
+func TestBufferSnapshot_ChunksCopyOnWrite(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abcd" {
+		buf.Write(r)
+	}
+	snapshot := buf.Snapshot()
+	for chunk := range buf.Chunks() {
+		clear(chunk)
+	}
+	if r := snapshot.At(2); r != 'c' {
+		t.Errorf("unexpected snapshot element:\nexp=%c\ngot=%c", 'c', r)
+	}
+}
+
+func TestReadOnlySnapshot_AtOutOfRangePanic(t *testing.T) {
+	defer func() { _ = recover() }()
+
+	_ = New[rune]().Snapshot().At(0)
+	t.Errorf("expected At to panic")
+}
@@ -0,0 +1,350 @@
+package gobuffer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// RefillTimeoutError is reported when a Buffer times out waiting for elements from its source (see
+// Buffer.WithRefillTimeout). Besides errors.Is the error may be identified by its Timeout method (like
+// os.ErrDeadlineExceeded).
+var RefillTimeoutError error = refillTimeoutError{}
+
+type refillTimeoutError struct{}
+
+func (refillTimeoutError) Error() string   { return "source refill timed out" }
+func (refillTimeoutError) Timeout() bool   { return true }
+func (refillTimeoutError) Temporary() bool { return true }
+
+// NotReadyError may be returned by a non-blocking Source having no elements available at the moment (see
+// ChanSource.NonBlocking). Unlike other errors NotReadyError doesn't stop the Buffer from pulling from the source.
+// Instead Buffer.Next returns false and a later call to Buffer.Next pulls from the source again.
+var NotReadyError = errors.New("source has no elements available")
+
+// Source is a source of elements for a Buffer (see NewFromSource). The semantics of Read follow io.Reader. That
+// is, Read reads up to len(p) elements into p and returns the number of elements read. When the source is
+// exhausted io.EOF is returned. A non-blocking source may return NotReadyError when no elements are available.
+type Source[T any] interface {
+	Read(p []T) (n int, err error)
+}
+
+// SourceFunc is an adapter allowing an ordinary function to be used as a Source (like http.HandlerFunc).
+type SourceFunc[T any] func(p []T) (n int, err error)
+
+// Read calls f(p).
+func (f SourceFunc[T]) Read(p []T) (n int, err error) {
+	return f(p)
+}
+
+// maxEmptyReads is the number of consecutive reads returning no elements and no error before a source is
+// considered broken (see io.ErrNoProgress).
+const maxEmptyReads = 100
+
+// source holds the state of the Source attached to a Buffer.
+type source[T any] struct {
+	src Source[T]
+	err error // err holds the error that stopped the pulling from the source (io.EOF at the end of the source).
+	// prefetch delivers elements read by a background goroutine (nil if not prefetching).
+	prefetch chan prefetched[T]
+	stop     chan struct{} // stop is closed to stop the prefetching goroutine.
+	retry    RetryPolicy
+	timeout  time.Duration // timeout holds the maximum time to wait for a refill (0 if no timeout).
+	// pending holds the transient error of the last pull (RefillTimeoutError, NotReadyError or BufferFullError)
+	// or nil.
+	pending error
+	// held holds the prefetched elements not yet written as the Buffer was full (see Buffer.WithMaxElements).
+	held prefetched[T]
+}
+
+// prefetched holds elements read from a source by the prefetching goroutine.
+type prefetched[T any] struct {
+	elements []T
+	err      error
+}
+
+// NewFromSource creates a new Buffer pulling elements from the provided source. When Buffer.Next finds no
+// unconsumed elements then more elements are pulled from the source automatically. Elements may still be written
+// to the Buffer. Such elements are interleaved with the elements pulled from the source in write order. Note
+// that only Buffer.Next pulls from the source. Other methods (e.g. Buffer.Buffered) only see elements already
+// pulled.
+//
+// When the source returns an error (e.g. io.EOF) no more elements are pulled from the source.
+func NewFromSource[T any](src Source[T]) *Buffer[T] {
+	return NewFromSourceWithSize(src, 10, 5)
+}
+
+// NewFromSourceWithSize creates a new Buffer pulling elements from the provided source (see NewFromSource). The
+// Buffer has the specified row size and number of pre-allocated rows (see NewWithSize).
+func NewFromSourceWithSize[T any](src Source[T], rowSize, rows int) *Buffer[T] {
+	buf := NewWithSize[T](rowSize, rows)
+	buf.source = &source[T]{src: src}
+	return buf
+}
+
+// Err returns the error that stopped the Buffer from pulling elements from its source (see NewFromSource). Like
+// bufio.Scanner.Err, the end of the source (io.EOF) is not reported as an error and nil is returned. If the Buffer
+// has no source then nil is returned.
+//
+// If the last pull from the source timed out (see Buffer.WithRefillTimeout) then RefillTimeoutError is returned.
+// If the source had no elements available at the last pull then NotReadyError is returned. If the Buffer was
+// full at the last pull (see Buffer.WithMaxElements) then BufferFullError is returned.
+func (b *Buffer[T]) Err() error {
+	if b.source == nil || errors.Is(b.source.err, io.EOF) {
+		return nil
+	}
+	if b.source.pending != nil {
+		return b.source.pending
+	}
+	return b.source.err
+}
+
+// EnsureBuffered pulls elements from the source of the Buffer (see NewFromSource) until at least n unconsumed
+// elements are buffered. If fewer than n elements are buffered at the end of the source (or if the Buffer has no
+// source) then io.EOF is returned. If the source fails then the source error is returned (see Buffer.Err).
+func (b *Buffer[T]) EnsureBuffered(n int) error {
+	return b.WaitFor(context.Background(), n)
+}
+
+// WaitFor pulls elements from the source of the Buffer like Buffer.EnsureBuffered until at least n unconsumed
+// elements are buffered. If the context is done before that then the context error is returned. The context is
+// checked between the pulls from the source. Hence, a blocked read from the source is only interrupted if the
+// Buffer has a refill timeout (see Buffer.WithRefillTimeout).
+func (b *Buffer[T]) WaitFor(ctx context.Context, n int) error {
+	for b.Buffered() < n {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if b.source == nil || !b.pull() {
+			if err := b.Err(); err != nil {
+				return err
+			}
+			return io.EOF
+		}
+	}
+	return nil
+}
+
+// RetryPolicy controls how transient errors returned by the source of a Buffer are retried (see
+// Buffer.WithRetry).
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts to read from the source (including the first attempt). If
+	// MaxAttempts is <= 1 then errors are not retried.
+	MaxAttempts int
+	// Backoff returns the time to wait before the retry following the specified failed attempt (starting at 1).
+	// If Backoff is nil then a retry is done immediately.
+	Backoff func(attempt int) time.Duration
+	// Retryable returns true if the error is transient and should be retried. If Retryable is nil then all errors
+	// except io.EOF are retried.
+	Retryable func(err error) bool
+}
+
+// retryable returns true if the error returned by the specified failed attempt should be retried.
+func (p RetryPolicy) retryable(err error, attempt int) bool {
+	if attempt >= p.MaxAttempts || errors.Is(err, io.EOF) {
+		return false
+	}
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// ExponentialBackoff returns a RetryPolicy.Backoff function doubling the wait time for each attempt starting at
+// base and never waiting more than max.
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		return min(d, max)
+	}
+}
+
+// WithRetry configures the policy used to retry transient errors returned by the source of the Buffer (see
+// NewFromSource). An error is only returned from the source (see Buffer.Err) when it is not retryable or the
+// maximum number of attempts has been reached. The Buffer is returned.
+//
+// The retry policy should be configured before any elements are pulled from the source (and before
+// Buffer.WithPrefetch). If the Buffer has no source then a panic is raised.
+func (b *Buffer[T]) WithRetry(policy RetryPolicy) *Buffer[T] {
+	if b.source == nil {
+		panic(usageError("illegal retry policy for buffer without source"))
+	}
+	b.source.retry = policy
+	return b
+}
+
+// WithPrefetch makes the Buffer pull elements from its source in a background goroutine. The goroutine keeps
+// (about) n elements read ahead so that Buffer.Next rarely has to wait for the source. The prefetched elements
+// are moved into the Buffer when Buffer.Next finds no unconsumed elements. The goroutine stops when the source
+// returns an error or when the prefetching is stopped (see Buffer.StopPrefetch). The Buffer is returned.
+//
+// The source must not be used by anyone else while prefetching. If n is <= 0 or the Buffer has no source then
+// a panic is raised. If the Buffer already prefetches then the call has no effect.
+func (b *Buffer[T]) WithPrefetch(n int) *Buffer[T] {
+	if n <= 0 {
+		panic(argumentError{"illegal non-positive prefetch size", n})
+	}
+	if b.source == nil {
+		panic(usageError("illegal prefetch of buffer without source"))
+	}
+	s := b.source
+	if s.prefetch != nil || s.err != nil {
+		return b
+	}
+	s.prefetch = make(chan prefetched[T], (n+b.rowSize-1)/b.rowSize)
+	s.stop = make(chan struct{})
+	go s.run(s.prefetch, s.stop, b.rowSize)
+	return b
+}
+
+// StopPrefetch stops the prefetching goroutine (see Buffer.WithPrefetch). Elements already prefetched are still
+// moved into the Buffer but no more elements are pulled from the source. If the Buffer doesn't prefetch then
+// the call has no effect.
+func (b *Buffer[T]) StopPrefetch() {
+	if b.source != nil && b.source.stop != nil {
+		close(b.source.stop)
+		b.source.stop = nil
+	}
+}
+
+// run reads elements from the source and delivers them on the prefetch channel until the source returns an
+// error or the prefetching is stopped.
+func (s *source[T]) run(prefetch chan<- prefetched[T], stop <-chan struct{}, size int) {
+	defer close(prefetch)
+	for {
+		elements := make([]T, size)
+		n, err := s.read(elements)
+		// Prefer stopping over delivering when the prefetching has been stopped during the read
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		select {
+		case prefetch <- prefetched[T]{elements: elements[:n], err: err}:
+		case <-stop:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// read reads at least one element from the source unless an error is returned. If the source repeatedly returns
+// no elements and no error then io.ErrNoProgress is returned.
+// Transient errors are retried according to the retry policy of the source (see Buffer.WithRetry).
+func (s *source[T]) read(p []T) (n int, err error) {
+	for attempt := 1; ; attempt++ {
+		n, err = s.readOnce(p)
+		if err == nil || !s.retry.retryable(err, attempt) {
+			return
+		}
+		if n > 0 {
+			// Deliver the elements read and retry on the next read
+			return n, nil
+		}
+		if s.retry.Backoff != nil {
+			time.Sleep(s.retry.Backoff(attempt))
+		}
+	}
+}
+
+// readOnce reads at least one element from the source unless an error is returned (see source.read).
+func (s *source[T]) readOnce(p []T) (n int, err error) {
+	for i := 0; i < maxEmptyReads; i++ {
+		if n, err = s.src.Read(p); n > 0 || err != nil {
+			return
+		}
+	}
+	return 0, io.ErrNoProgress
+}
+
+// WithRefillTimeout limits the time Buffer.Next (and Buffer.EnsureBuffered) waits for the source of the Buffer
+// to deliver more elements to d. When a refill times out Buffer.Next returns false and Buffer.Err returns
+// RefillTimeoutError. The refill continues in the background and a later call to Buffer.Next may return the
+// elements when they arrive. The Buffer is returned.
+//
+// As a blocking read can't be interrupted the source is read by a background goroutine. That is, if the Buffer
+// doesn't prefetch then prefetching of one row is enabled (see Buffer.WithPrefetch). If d is <= 0 or the Buffer
+// has no source then a panic is raised.
+func (b *Buffer[T]) WithRefillTimeout(d time.Duration) *Buffer[T] {
+	if d <= 0 {
+		panic(usageError("illegal non-positive refill timeout " + d.String()))
+	}
+	if b.source == nil {
+		panic(usageError("illegal refill timeout for buffer without source"))
+	}
+	b.source.timeout = d
+	return b.WithPrefetch(b.rowSize)
+}
+
+// pull pulls more elements from the source into the Buffer. False is returned if no more elements may be pulled
+// from the source.
+func (b *Buffer[T]) pull() bool {
+	s := b.source
+	if s.err != nil || b.frozen {
+		return false
+	}
+	room := -1 // room holds the number of elements that may be pulled (-1 if unbounded).
+	if b.maxElements > 0 {
+		if room = b.room(); room <= 0 {
+			s.pending = b.full()
+			return false
+		}
+	}
+	if s.prefetch != nil {
+		if len(s.held.elements) == 0 {
+			var timeout <-chan time.Time
+			if s.timeout > 0 {
+				timer := time.NewTimer(s.timeout)
+				defer timer.Stop()
+				timeout = timer.C
+			}
+			select {
+			case p, ok := <-s.prefetch:
+				if !ok {
+					// The prefetching has been stopped
+					s.pending = nil
+					s.err = io.EOF
+					return false
+				}
+				s.held = p
+			case <-timeout:
+				s.pending = RefillTimeoutError
+				return false
+			}
+		}
+		s.pending = nil
+		n := len(s.held.elements)
+		if room >= 0 {
+			n = min(n, room)
+		}
+		b.writeSlice(s.held.elements[:n])
+		if s.held.elements = s.held.elements[n:]; len(s.held.elements) == 0 {
+			s.err = s.held.err
+			s.held = prefetched[T]{}
+		}
+		return true
+	}
+	// Read directly into the row holding the write position
+	b.Grow(b.write.AbsolutePos() - b.startPos().AbsolutePos() + 1)
+	row, col := b.bufferPos(b.write)
+	dst := b.buffers[row][col:]
+	if room >= 0 {
+		dst = dst[:min(len(dst), room)]
+	}
+	n, err := s.read(dst)
+	from := b.write
+	b.write = b.write.Move(n)
+	b.sample()
+	b.notifyWatchers(from)
+	if errors.Is(err, NotReadyError) {
+		s.pending = NotReadyError
+		return n > 0
+	}
+	s.pending = nil
+	s.err = err
+	return true
+}
@@ -0,0 +1,319 @@
+package gobuffer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+)
+
+// sliceSource is a Source returning the elements of a slice at most max elements per read.
+type sliceSource[T any] struct {
+	elements []T
+	max      int
+	err      error // err is returned when all elements have been read (io.EOF if nil).
+}
+
+func (s *sliceSource[T]) Read(p []T) (int, error) {
+	if len(s.elements) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		return 0, io.EOF
+	}
+	n := copy(p[:min(len(p), s.max)], s.elements)
+	s.elements = s.elements[n:]
+	return n, nil
+}
+
+func TestNewFromSource(t *testing.T) {
+	buf := NewFromSourceWithSize[rune](&sliceSource[rune]{elements: []rune("let x = 42;"), max: 2}, 3, 1)
+	state := buf.State()
+	if got := readAll(buf); got != "let x = 42;" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "let x = 42;", got)
+	}
+	// Rollback works across refills
+	if err := buf.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	buf.Write('!')
+	if got := readAll(buf); got != "let x = 42;!" {
+		t.Errorf("unexpected content after rollback:\nexp=%s\ngot=%s", "let x = 42;!", got)
+	}
+}
+
+func TestSourceFunc(t *testing.T) {
+	next := 0
+	buf := NewFromSource[int](SourceFunc[int](func(p []int) (int, error) {
+		if next == 5 {
+			return 0, io.EOF
+		}
+		p[0] = next
+		next++
+		return 1, nil
+	}))
+	var got []int
+	for n, ok := buf.Pop(); ok; n, ok = buf.Pop() {
+		got = append(got, n)
+	}
+	if !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("unexpected elements:\nexp=%v\ngot=%v", []int{0, 1, 2, 3, 4}, got)
+	}
+}
+
+// emptySource is a Source never returning any elements.
+type emptySource struct{}
+
+func (emptySource) Read([]int) (int, error) { return 0, nil }
+
+func TestNewFromSource_NoProgress(t *testing.T) {
+	buf := NewFromSource[int](emptySource{})
+	if _, ok := buf.Next(); ok {
+		t.Errorf("unexpected next element")
+	}
+	if !errors.Is(buf.source.err, io.ErrNoProgress) {
+		t.Errorf("unexpected source error:\nexp=%v\ngot=%v", io.ErrNoProgress, buf.source.err)
+	}
+}
+
+func TestBufferWithPrefetch(t *testing.T) {
+	elements := make([]int, 100)
+	for i := range elements {
+		elements[i] = i
+	}
+	buf := NewFromSourceWithSize[int](&sliceSource[int]{elements: elements, max: 7}, 4, 1).WithPrefetch(10)
+	for i := range elements {
+		element, ok := buf.Next()
+		if !ok || element != i {
+			t.Fatalf("unexpected next:\nexp=%d\ngot=%d (%v)", i, element, ok)
+		}
+		buf.Consume()
+		buf.Commit()
+	}
+	if _, ok := buf.Next(); ok {
+		t.Errorf("unexpected next element")
+	}
+}
+
+// blockingSource is a Source returning one element per read and blocking when release is empty.
+type blockingSource struct {
+	release chan struct{}
+}
+
+func (s blockingSource) Read(p []int) (int, error) {
+	<-s.release
+	p[0] = 1
+	return 1, nil
+}
+
+func TestBufferStopPrefetch(t *testing.T) {
+	src := blockingSource{release: make(chan struct{}, 2)}
+	src.release <- struct{}{}
+	src.release <- struct{}{}
+	buf := NewFromSourceWithSize[int](src, 4, 1).WithPrefetch(1)
+	// Wait until the prefetched element is available before stopping
+	if _, ok := buf.Next(); !ok {
+		t.Fatalf("expected a prefetched element")
+	}
+	buf.Consume()
+	time.Sleep(10 * time.Millisecond)
+	buf.StopPrefetch()
+	close(src.release)
+	n := 0
+	for _, ok := buf.Next(); ok; _, ok = buf.Next() {
+		buf.Consume()
+		n++
+	}
+	if n > 2 {
+		t.Errorf("unexpected elements after stop: %d", n)
+	}
+}
+
+func TestBufferWithPrefetch_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		buf  *Buffer[int]
+		n    int
+	}{
+		{"non-positive", NewFromSource[int](emptySource{}), 0},
+		{"no source", New[int](), 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() { _ = recover() }()
+
+			test.buf.WithPrefetch(test.n)
+			t.Errorf("expected WithPrefetch to panic")
+		})
+	}
+}
+
+func TestBufferErr(t *testing.T) {
+	failure := errors.New("connection reset")
+	tests := []struct {
+		name string
+		buf  *Buffer[rune]
+		exp  error
+	}{
+		{"no source", New[rune](), nil},
+		{"end of source", NewFromSource[rune](&sliceSource[rune]{elements: []rune("ab"), max: 1}), nil},
+		{"failing source", NewFromSource[rune](&sliceSource[rune]{elements: []rune("ab"), max: 1, err: failure}), failure},
+		{"prefetching failing source",
+			NewFromSource[rune](&sliceSource[rune]{elements: []rune("ab"), max: 1, err: failure}).WithPrefetch(1), failure},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.buf.Write('-')
+			test.buf.Consume()
+			if err := test.buf.Err(); err != nil {
+				t.Errorf("unexpected error before end:\nexp=%v\ngot=%v", nil, err)
+			}
+			_ = readAll(test.buf)
+			if err := test.buf.Err(); !errors.Is(err, test.exp) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", test.exp, err)
+			}
+		})
+	}
+}
+
+// flakySource is a Source failing with err before every successful read until failures is exhausted.
+type flakySource struct {
+	Source[rune]
+	err      error
+	failures int
+	attempts int
+}
+
+func (s *flakySource) Read(p []rune) (int, error) {
+	s.attempts++
+	if s.failures > 0 && s.attempts%2 == 1 {
+		s.failures--
+		return 0, s.err
+	}
+	return s.Source.Read(p)
+}
+
+func TestBufferWithRetry(t *testing.T) {
+	transient := errors.New("temporary failure")
+	permanent := errors.New("permanent failure")
+	tests := []struct {
+		name     string
+		err      error
+		failures int
+		policy   RetryPolicy
+		exp      string
+		expErr   error
+	}{
+		{"no retry", transient, 1, RetryPolicy{}, "", transient},
+		{"retried", transient, 3, RetryPolicy{MaxAttempts: 2}, "abc", nil},
+		{"max attempts", transient, 3, RetryPolicy{MaxAttempts: 1}, "", transient},
+		{"not retryable", permanent, 1, RetryPolicy{
+			MaxAttempts: 3,
+			Retryable:   func(err error) bool { return err == transient },
+		}, "", permanent},
+		{"backoff", transient, 2, RetryPolicy{
+			MaxAttempts: 2,
+			Backoff:     ExponentialBackoff(time.Millisecond, 2*time.Millisecond),
+		}, "abc", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := &flakySource{
+				Source:   &sliceSource[rune]{elements: []rune("abc"), max: 1},
+				err:      test.err,
+				failures: test.failures,
+			}
+			buf := NewFromSource[rune](src).WithRetry(test.policy)
+			if got := readAll(buf); got != test.exp {
+				t.Errorf("unexpected content:\nexp=%s\ngot=%s", test.exp, got)
+			}
+			if err := buf.Err(); !errors.Is(err, test.expErr) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	exp := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond}
+	for i, d := range exp {
+		if got := backoff(i + 1); got != d {
+			t.Errorf("unexpected backoff for attempt %d:\nexp=%v\ngot=%v", i+1, d, got)
+		}
+	}
+}
+
+func TestBufferEnsureBuffered(t *testing.T) {
+	failure := errors.New("connection reset")
+	tests := []struct {
+		name string
+		buf  *Buffer[rune]
+		n    int
+		err  error
+	}{
+		{"no source", New[rune](), 3, io.EOF},
+		{"already buffered", New[rune](), 1, nil},
+		{"pulled", NewFromSource[rune](&sliceSource[rune]{elements: []rune("abcdef"), max: 2}), 5, nil},
+		{"end of source", NewFromSource[rune](&sliceSource[rune]{elements: []rune("abc"), max: 2}), 5, io.EOF},
+		{"failing source", NewFromSource[rune](&sliceSource[rune]{elements: []rune("abc"), max: 2, err: failure}), 5,
+			failure},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.buf.Write('-')
+			test.buf.Write('-')
+			test.buf.Consume()
+			err := test.buf.EnsureBuffered(test.n)
+			if !errors.Is(err, test.err) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", test.err, err)
+			}
+			if err == nil && test.buf.Buffered() < test.n {
+				t.Errorf("unexpected buffered:\nexp>=%d\ngot=%d", test.n, test.buf.Buffered())
+			}
+		})
+	}
+}
+
+func TestBufferWithRefillTimeout(t *testing.T) {
+	src := blockingSource{release: make(chan struct{}, 1)}
+	buf := NewFromSourceWithSize[int](src, 4, 1).WithRefillTimeout(10 * time.Millisecond)
+	if _, ok := buf.Next(); ok {
+		t.Fatalf("unexpected next element")
+	}
+	err := buf.Err()
+	if !errors.Is(err, RefillTimeoutError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", RefillTimeoutError, err)
+	}
+	if timeout, ok := err.(interface{ Timeout() bool }); !ok || !timeout.Timeout() {
+		t.Errorf("expected a timeout error")
+	}
+	if err := buf.EnsureBuffered(1); !errors.Is(err, RefillTimeoutError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", RefillTimeoutError, err)
+	}
+	// The refill continues in the background
+	src.release <- struct{}{}
+	buf.source.timeout = time.Second
+	if _, ok := buf.Next(); !ok {
+		t.Fatalf("expected element after release")
+	}
+	if err := buf.Err(); err != nil {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", nil, err)
+	}
+	buf.StopPrefetch()
+	close(src.release)
+}
+
+func TestBufferWaitFor_Canceled(t *testing.T) {
+	buf := NewFromSourceWithSize[rune](&sliceSource[rune]{elements: []rune("abc"), max: 1}, 4, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := buf.WaitFor(ctx, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", context.Canceled, err)
+	}
+	if err := buf.WaitFor(context.Background(), 2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package gobuffer
+
+import (
+	"math/bits"
+	"runtime"
+	"sync/atomic"
+)
+
+// SPSCBuffer is a bounded buffer safe for concurrent use by a single producer goroutine (calling Write) and a
+// single consumer goroutine (calling the other methods). The elements are held in a ring and the producer and
+// consumer are synchronized using atomic indexes instead of a mutex (see SyncBuffer).
+//
+// The consumer has the same lookahead (Next and Consume) and rollback (State and Rollback) as for a Buffer. The
+// ring slots of consumed elements are handed back to the producer by SPSCBuffer.Commit. When the ring is full
+// Write waits for the consumer to commit.
+type SPSCBuffer[T any] struct {
+	ring []T
+	mask uint64
+	// write holds the index of the next element to write (published by the producer).
+	write atomic.Uint64
+	// commit holds the index before which the ring slots may be reused (published by the consumer).
+	commit atomic.Uint64
+	read   uint64 // read holds the index of the next element to read (owned by the consumer).
+}
+
+var (
+	_ Writer[any] = (*SPSCBuffer[any])(nil)
+	_ View[any]   = (*SPSCBuffer[any])(nil)
+)
+
+// spscStateRow marks the write position of states created by a SPSCBuffer.
+const spscStateRow = -1
+
+// NewSPSC creates a new SPSCBuffer holding at least the specified number of elements (the capacity is rounded up
+// to a power of two). If capacity is <= 0 then a panic is raised.
+func NewSPSC[T any](capacity int) *SPSCBuffer[T] {
+	if capacity <= 0 {
+		panic(argumentError{"illegal non-positive capacity", capacity})
+	}
+	size := uint64(1) << bits.Len64(uint64(capacity-1))
+	return &SPSCBuffer[T]{ring: make([]T, size), mask: size - 1}
+}
+
+// Write writes an element to the SPSCBuffer. If the ring is full then Write waits until the consumer commits (see
+// SPSCBuffer.Commit). Write may only be called by the producer goroutine.
+func (b *SPSCBuffer[T]) Write(element T) {
+	w := b.write.Load()
+	for w-b.commit.Load() == uint64(len(b.ring)) {
+		runtime.Gosched()
+	}
+	b.ring[w&b.mask] = element
+	b.write.Store(w + 1)
+}
+
+// Next returns the next element from the SPSCBuffer (see Buffer.Next). If there are no unread elements then
+// false is returned. Next doesn't wait for the producer.
+func (b *SPSCBuffer[T]) Next() (element T, ok bool) {
+	if b.read == b.write.Load() {
+		return
+	}
+	return b.ring[b.read&b.mask], true
+}
+
+// Consume consumes the next element (returned by SPSCBuffer.Next). Consuming from an empty SPSCBuffer has no
+// effect.
+func (b *SPSCBuffer[T]) Consume() {
+	if b.read != b.write.Load() {
+		b.read++
+	}
+}
+
+// Buffered returns the number of unconsumed elements in the SPSCBuffer.
+func (b *SPSCBuffer[T]) Buffered() int {
+	return int(b.write.Load() - b.read)
+}
+
+// State returns a state that may be used to roll back to the current read position (see Buffer.State).
+func (b *SPSCBuffer[T]) State() State {
+	return newState(position{Col: int(b.read)}, position{Row: spscStateRow})
+}
+
+// Rollback resets the read position to the provided state (see Buffer.Rollback). If the provided state is the
+// "zero state" then a ZeroStateError is returned. If the state wasn't created by the SPSCBuffer or was created
+// before the last commit then an IllegalStateError is returned.
+func (b *SPSCBuffer[T]) Rollback(state State) error {
+	if !state.init {
+		return ZeroStateError
+	}
+	read := uint64(state.read.Col)
+	if state.write.Row != spscStateRow || read < b.commit.Load() || read > b.read {
+		return IllegalStateError
+	}
+	b.read = read
+	return nil
+}
+
+// Commit hands the ring slots of all consumed elements back to the producer. States created before the commit
+// are no longer valid.
+func (b *SPSCBuffer[T]) Commit() {
+	b.commit.Store(b.read)
+}
@@ -0,0 +1,62 @@
+package gobuffer
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestSPSCBuffer(t *testing.T) {
+	buf := NewSPSC[int](6)
+	if n := len(buf.ring); n != 8 {
+		t.Errorf("unexpected capacity:\nexp=%d\ngot=%d", 8, n)
+	}
+	go func() {
+		for i := range 1000 {
+			buf.Write(i)
+		}
+	}()
+	for i := 0; i < 1000; {
+		state := buf.State()
+		v, ok := buf.Next()
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		// Lookahead and rollback before consuming for real
+		buf.Consume()
+		if err := buf.Rollback(state); err != nil {
+			t.Fatalf("unexpected rollback error: %v", err)
+		}
+		if v != i {
+			t.Fatalf("unexpected next:\nexp=%d\ngot=%d", i, v)
+		}
+		buf.Consume()
+		buf.Commit()
+		i++
+	}
+}
+
+func TestSPSCBuffer_Rollback(t *testing.T) {
+	buf := NewSPSC[int](4)
+	buf.Write(1)
+	state := buf.State()
+	buf.Consume()
+	buf.Commit()
+	tests := []struct {
+		name  string
+		state State
+		err   error
+	}{
+		{"zero", State{}, ZeroStateError},
+		{"committed", state, IllegalStateError},
+		{"foreign", NewSliceReader([]int{1}).State(), IllegalStateError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := buf.Rollback(test.state); !errors.Is(err, test.err) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", test.err, err)
+			}
+		})
+	}
+}
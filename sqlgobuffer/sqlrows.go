@@ -0,0 +1,50 @@
+// Package sqlgobuffer provides database/sql adapters for gobuffer. The adapters are kept in a separate package to
+// avoid linking database/sql into every user of gobuffer.
+package sqlgobuffer
+
+import (
+	"database/sql"
+	"io"
+
+	"github.com/habak67/gobuffer"
+)
+
+// RowsSource is a gobuffer.Source of elements scanned from the rows of a database/sql query result. The rows are
+// scanned lazily, when the Buffer pulls more elements from the source. The rows are closed when all rows have been
+// read or an error occurs.
+type RowsSource[T any] struct {
+	rows *sql.Rows
+	scan func(*sql.Rows) (T, error)
+}
+
+// NewRowsSource creates a new RowsSource scanning the provided rows using the scan function. The scan function is
+// called once for each row and should call sql.Rows.Scan to create the element of the row.
+func NewRowsSource[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) *RowsSource[T] {
+	return &RowsSource[T]{rows: rows, scan: scan}
+}
+
+// Read scans up to len(p) rows into p. When all rows have been read io.EOF is returned. If the iteration of the
+// rows fails (see sql.Rows.Err) or the scan function returns an error then that error is returned.
+func (s *RowsSource[T]) Read(p []T) (n int, err error) {
+	for n < len(p) {
+		if !s.rows.Next() {
+			if err = s.rows.Err(); err == nil {
+				err = io.EOF
+			}
+			_ = s.rows.Close()
+			return
+		}
+		if p[n], err = s.scan(s.rows); err != nil {
+			_ = s.rows.Close()
+			return
+		}
+		n++
+	}
+	return
+}
+
+// NewFromRows creates a new Buffer pulling elements scanned from the provided rows (see NewRowsSource and
+// gobuffer.NewFromSource).
+func NewFromRows[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) *gobuffer.Buffer[T] {
+	return gobuffer.NewFromSource[T](NewRowsSource(rows, scan))
+}
@@ -0,0 +1,100 @@
+package sqlgobuffer
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+// numbersDriver is a database/sql driver where every query returns the rows of a single column holding the
+// numbers 1 to 5. If the query is "fail" then the iteration fails after the third row.
+type numbersDriver struct{}
+
+func (numbersDriver) Open(string) (driver.Conn, error) { return numbersConn{}, nil }
+
+type numbersConn struct{}
+
+func (numbersConn) Prepare(query string) (driver.Stmt, error) { return numbersStmt{query}, nil }
+func (numbersConn) Close() error                              { return nil }
+func (numbersConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type numbersStmt struct{ query string }
+
+func (numbersStmt) Close() error  { return nil }
+func (numbersStmt) NumInput() int { return 0 }
+func (numbersStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s numbersStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &numbersRows{fail: s.query == "fail"}, nil
+}
+
+type numbersRows struct {
+	n    int64
+	fail bool
+}
+
+var failedRowsError = errors.New("failed rows")
+
+func (*numbersRows) Columns() []string { return []string{"n"} }
+func (*numbersRows) Close() error      { return nil }
+func (r *numbersRows) Next(dest []driver.Value) error {
+	switch {
+	case r.fail && r.n == 3:
+		return failedRowsError
+	case r.n == 5:
+		return io.EOF
+	}
+	r.n++
+	dest[0] = r.n
+	return nil
+}
+
+func init() {
+	sql.Register("gobuffer-numbers", numbersDriver{})
+}
+
+func scanNumber(rows *sql.Rows) (n int, err error) {
+	err = rows.Scan(&n)
+	return
+}
+
+func TestNewFromRows(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		exp   []int
+		err   error
+	}{
+		{"all rows", "numbers", []int{1, 2, 3, 4, 5}, nil},
+		{"failed rows", "fail", []int{1, 2, 3}, failedRowsError},
+	}
+	db, err := sql.Open("gobuffer-numbers", "")
+	if err != nil {
+		t.Fatalf("unexpected open error: %v", err)
+	}
+	defer db.Close()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows, err := db.Query(test.query)
+			if err != nil {
+				t.Fatalf("unexpected query error: %v", err)
+			}
+			buf := NewFromRows(rows, scanNumber)
+			var got []int
+			for n, ok := buf.Next(); ok; n, ok = buf.Next() {
+				got = append(got, n)
+				buf.Consume()
+			}
+			if !slices.Equal(got, test.exp) {
+				t.Errorf("unexpected elements:\nexp=%v\ngot=%v", test.exp, got)
+			}
+			if err := buf.Err(); !errors.Is(err, test.err) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", test.err, err)
+			}
+		})
+	}
+}
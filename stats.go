@@ -0,0 +1,78 @@
+package gobuffer
+
+import "math/bits"
+
+// Stats holds statistics collected by a Buffer (see Buffer.WithStats). The statistics are intended for tuning the
+// row size and commit cadence of a Buffer.
+type Stats struct {
+	// PeakBuffered holds the highest number of unconsumed elements sampled.
+	PeakBuffered int
+	// Occupancy holds the distribution of the number of unconsumed elements sampled at each write and consume.
+	Occupancy Histogram
+}
+
+// Histogram is a lightweight histogram of non-negative values using power of two buckets. That is, Counts[0]
+// holds the number of samples with the value 0 and Counts[i] (i > 0) holds the number of samples with a value
+// in the range [2^(i-1), 2^i-1].
+type Histogram struct {
+	Counts [bits.UintSize + 1]uint64
+}
+
+// add adds a sample to the histogram.
+func (h *Histogram) add(v int) {
+	h.Counts[bits.Len(uint(v))]++
+}
+
+// Count returns the number of samples in the histogram.
+func (h Histogram) Count() (n uint64) {
+	for _, c := range h.Counts {
+		n += c
+	}
+	return
+}
+
+// Quantile returns an upper bound of the q-quantile (0 <= q <= 1) of the sampled values. That is, the upper bound
+// of the bucket holding the q-quantile is returned. For example, Quantile(0.99) returns a value that at least 99%
+// of the samples are less than or equal to. If the histogram holds no samples then 0 is returned.
+func (h Histogram) Quantile(q float64) int {
+	rank := uint64(q * float64(h.Count()))
+	var n uint64
+	for i, c := range h.Counts {
+		n += c
+		if c > 0 && n >= rank {
+			return 1<<i - 1
+		}
+	}
+	return 0
+}
+
+// WithStats makes the Buffer collect statistics (see Buffer.Stats). The number of unconsumed elements is sampled
+// at each write and consume. The Buffer is returned.
+func (b *Buffer[T]) WithStats() *Buffer[T] {
+	if b.stats == nil {
+		b.stats = &Stats{}
+	}
+	return b
+}
+
+// Stats returns the statistics collected by the Buffer. If the Buffer doesn't collect statistics (see
+// Buffer.WithStats) then empty statistics are returned.
+func (b *Buffer[T]) Stats() Stats {
+	if b.stats == nil {
+		return Stats{}
+	}
+	return *b.stats
+}
+
+// sample samples the number of unconsumed elements if the Buffer collects statistics.
+func (b *Buffer[T]) sample() {
+	if b.stats != nil {
+		b.stats.sample(b.Buffered())
+	}
+}
+
+// sample adds a sample of the number of unconsumed elements to the statistics.
+func (s *Stats) sample(buffered int) {
+	s.PeakBuffered = max(s.PeakBuffered, buffered)
+	s.Occupancy.add(buffered)
+}
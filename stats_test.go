@@ -0,0 +1,62 @@
+package gobuffer
+
+import "testing"
+
+func TestBufferStats(t *testing.T) {
+	buf := NewWithSize[int](3, 1).WithStats()
+	// Samples 1, 2, ..., 10 while writing and 9, 8, ..., 0 while consuming
+	for i := 0; i < 10; i++ {
+		buf.Write(i)
+	}
+	for i := 0; i < 10; i++ {
+		buf.Consume()
+	}
+	stats := buf.Stats()
+	if stats.PeakBuffered != 10 {
+		t.Errorf("unexpected peak:\nexp=%d\ngot=%d", 10, stats.PeakBuffered)
+	}
+	if n := stats.Occupancy.Count(); n != 20 {
+		t.Errorf("unexpected sample count:\nexp=%d\ngot=%d", 20, n)
+	}
+	exp := []uint64{1, 2, 4, 8, 5}
+	for i, c := range exp {
+		if stats.Occupancy.Counts[i] != c {
+			t.Errorf("unexpected count in bucket %d:\nexp=%d\ngot=%d", i, c, stats.Occupancy.Counts[i])
+		}
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	var h Histogram
+	if q := h.Quantile(0.5); q != 0 {
+		t.Errorf("unexpected quantile of empty histogram: %d", q)
+	}
+	for i := 0; i < 98; i++ {
+		h.add(3)
+	}
+	h.add(100)
+	h.add(1000)
+	tests := []struct {
+		q   float64
+		exp int
+	}{
+		{0, 3},
+		{0.5, 3},
+		{0.98, 3},
+		{0.99, 127},
+		{1, 1023},
+	}
+	for _, test := range tests {
+		if got := h.Quantile(test.q); got != test.exp {
+			t.Errorf("unexpected quantile %v:\nexp=%d\ngot=%d", test.q, test.exp, got)
+		}
+	}
+}
+
+func TestBufferStats_Disabled(t *testing.T) {
+	buf := New[int]()
+	buf.Write(1)
+	if stats := buf.Stats(); stats.PeakBuffered != 0 || stats.Occupancy.Count() != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,51 @@
+package gobuffer
+
+// RowStorage holds the rows of a Buffer created by NewWithStorage. Rows are identified by their row number counted
+// from the creation of the Buffer (that is, the row number isn't affected by Buffer.Commit).
+//
+// The Buffer references the rows returned by RowStorage.GetRow until they are released by RowStorage.TrimBefore
+// or replaced by RowStorage.PutRow. The storage must therefore not reuse the memory of a row before that.
+type RowStorage[T any] interface {
+	// GetRow returns the row with the specified row number, allocating it if not already held by the storage.
+	// The returned row must have a length equal to the Buffer row size.
+	GetRow(row int) []T
+	// PutRow replaces the row with the specified row number. The Buffer replaces a row when copying a row shared
+	// with snapshots (see Buffer.Snapshot).
+	PutRow(row int, elements []T)
+	// TrimBefore releases all rows before the specified row number (see Buffer.Commit).
+	TrimBefore(row int)
+}
+
+// NewWithStorage creates a new Buffer with the specified row size keeping its rows in the provided storage. The
+// Buffer is pre-allocated with the specified number of rows. If row size or number of rows is <= 0 then a panic
+// is raised.
+func NewWithStorage[T any](storage RowStorage[T], rowSize, rows int) (buf *Buffer[T]) {
+	if rowSize <= 0 {
+		panic(argumentError{"illegal non-positive row size", rowSize})
+	}
+	if rows <= 0 {
+		panic(argumentError{"illegal non-positive number of rows", rows})
+	}
+	buf = &Buffer[T]{
+		rowSize: rowSize,
+		buffers: make([][]T, 0, rows),
+		read:    position{rowSize: rowSize},
+		write:   position{rowSize: rowSize},
+		pinned:  -1,
+		storage: storage,
+	}
+	buf.Grow(rows * rowSize)
+	return
+}
+
+// newRow returns a new row with the specified row number.
+func (b *Buffer[T]) newRow(row int) []T {
+	if b.storage == nil {
+		return make([]T, b.rowSize)
+	}
+	elements := b.storage.GetRow(row)
+	if len(elements) != b.rowSize {
+		panic(argumentError{"illegal row length from storage", len(elements)})
+	}
+	return elements
+}
@@ -0,0 +1,63 @@
+package gobuffer
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// mapStorage is a RowStorage keeping the rows in a map.
+type mapStorage struct {
+	rowSize int
+	rows    map[int][]rune
+}
+
+func (s *mapStorage) GetRow(row int) []rune {
+	if s.rows[row] == nil {
+		s.rows[row] = make([]rune, s.rowSize)
+	}
+	return s.rows[row]
+}
+
+func (s *mapStorage) PutRow(row int, elements []rune) {
+	s.rows[row] = elements
+}
+
+func (s *mapStorage) TrimBefore(row int) {
+	for r := range s.rows {
+		if r < row {
+			delete(s.rows, r)
+		}
+	}
+}
+
+func TestNewWithStorage(t *testing.T) {
+	storage := &mapStorage{rowSize: 3, rows: map[int][]rune{}}
+	buf := NewWithStorage[rune](storage, 3, 2)
+	for _, r := range "abcdefgh" {
+		buf.Write(r)
+	}
+	for range 4 {
+		buf.Next()
+		buf.Consume()
+	}
+	buf.Commit()
+	if got := slices.Sorted(maps.Keys(storage.rows)); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("unexpected stored rows:\nexp=%v\ngot=%v", []int{1, 2}, got)
+	}
+	if got := string(storage.rows[2][:2]); got != "gh" {
+		t.Errorf("unexpected stored row:\nexp=%s\ngot=%s", "gh", got)
+	}
+	// Modifying rows shared with a snapshot replaces the stored rows
+	snapshot := buf.Snapshot()
+	buf.Apply(func(r *rune) { *r -= 'a' - 'A' })
+	if got := string(storage.rows[1]); got != "dEF" {
+		t.Errorf("unexpected stored row:\nexp=%s\ngot=%s", "dEF", got)
+	}
+	if got := string(slices.Collect(snapshot.All())); got != "efgh" {
+		t.Errorf("unexpected snapshot content:\nexp=%s\ngot=%s", "efgh", got)
+	}
+	if got := readAll(buf); got != "EFGH" {
+		t.Errorf("unexpected buffer content:\nexp=%s\ngot=%s", "EFGH", got)
+	}
+}
@@ -0,0 +1,67 @@
+package gobuffer
+
+import "strconv"
+
+// String returns the unconsumed elements in the Buffer rendered as a string. The elements of a rune or byte Buffer
+// (like a ByteBuffer) are rendered as text. Other elements are rendered as a bracketed list separated by spaces
+// (like fmt.Sprint of a slice). Errors, elements having a String method, strings, booleans and numbers are
+// rendered like fmt does while elements of other types are rendered as "?". No elements are consumed.
+func (b *Buffer[T]) String() string {
+	elements := b.ToSlice()
+	switch s := any(elements).(type) {
+	case []rune:
+		return string(s)
+	case []byte:
+		return string(s)
+	}
+	text := []byte{'['}
+	for i, element := range elements {
+		if i > 0 {
+			text = append(text, ' ')
+		}
+		text = appendElement(text, element)
+	}
+	return string(append(text, ']'))
+}
+
+// appendElement appends the element rendered as text to the provided slice. The element is rendered using strconv
+// instead of fmt to keep the package free from fmt (see argumentError).
+func appendElement(text []byte, element any) []byte {
+	switch e := element.(type) {
+	case nil:
+		return append(text, "<nil>"...)
+	case error:
+		return append(text, e.Error()...)
+	case interface{ String() string }:
+		return append(text, e.String()...)
+	case string:
+		return append(text, e...)
+	case bool:
+		return strconv.AppendBool(text, e)
+	case int:
+		return strconv.AppendInt(text, int64(e), 10)
+	case int8:
+		return strconv.AppendInt(text, int64(e), 10)
+	case int16:
+		return strconv.AppendInt(text, int64(e), 10)
+	case int32:
+		return strconv.AppendInt(text, int64(e), 10)
+	case int64:
+		return strconv.AppendInt(text, e, 10)
+	case uint:
+		return strconv.AppendUint(text, uint64(e), 10)
+	case uint16:
+		return strconv.AppendUint(text, uint64(e), 10)
+	case uint32:
+		return strconv.AppendUint(text, uint64(e), 10)
+	case uint64:
+		return strconv.AppendUint(text, e, 10)
+	case uintptr:
+		return strconv.AppendUint(text, uint64(e), 10)
+	case float32:
+		return strconv.AppendFloat(text, float64(e), 'g', -1, 32)
+	case float64:
+		return strconv.AppendFloat(text, e, 'g', -1, 64)
+	}
+	return append(text, '?')
+}
@@ -0,0 +1,48 @@
+package gobuffer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// newBuffer returns a Buffer holding the provided elements.
+func newBuffer[T any](elements ...T) *Buffer[T] {
+	buf := New[T]()
+	buf.WriteSlice(elements)
+	return buf
+}
+
+func TestBufferString(t *testing.T) {
+	runes := NewWithSize[rune](2, 1)
+	for _, r := range "let x" {
+		runes.Write(r)
+	}
+	runes.Pop()
+	bytes := newByteBuffer("abc")
+	ints := NewWithSize[int](2, 1)
+	for i := range 3 {
+		ints.Write(i)
+	}
+	tests := []struct {
+		name string
+		buf  fmt.Stringer
+		exp  string
+	}{
+		{"runes", runes, "et x"},
+		{"bytes", bytes, "abc"},
+		{"ints", ints, "[0 1 2]"},
+		{"floats", newBuffer(0.5, float32(2)), "[0.5 2]"},
+		{"strings", newBuffer("a b", "c"), "[a b c]"},
+		{"errors", newBuffer(error(FrozenError), nil), "[write to frozen buffer <nil>]"},
+		{"stringers", newBuffer(time.Second, time.Millisecond), "[1s 1ms]"},
+		{"structs", newBuffer(struct{}{}), "[?]"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.buf.String(); got != test.exp {
+				t.Errorf("unexpected string:\nexp=%s\ngot=%s", test.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,415 @@
+package gobuffer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+var ClosedError = errors.New("write to closed buffer")
+
+// SyncBuffer is a Buffer safe for concurrent use. All methods are protected by a mutex. Hence, several goroutines
+// may write concurrently (e.g. to fan in elements from parallel readers into one stream). The writes are
+// serialized and the elements of a single Write (or WriteSlice) are never interleaved with elements of other
+// writes (unless the SyncBuffer is bounded, see SyncBuffer.WithMaxBuffered).
+//
+// Note that lookahead and rollback (Next, Consume, State and Rollback) assume a single consumer. If several
+// goroutines consume from the same SyncBuffer then an element returned by Next may be consumed by another
+// goroutine before the call to Consume.
+type SyncBuffer[T any] struct {
+	mu      sync.Mutex
+	buf     *Buffer[T]
+	changed chan struct{} // changed is closed when the SyncBuffer changes (nil if no one is waiting).
+	// maxBuffered holds the maximum number of unconsumed elements (0 if unbounded, see WithMaxBuffered).
+	maxBuffered int
+	closed      bool // closed is true when the producer has signaled the end of the elements (see Close).
+	// notify receives a value when the SyncBuffer becomes non-empty (nil if not requested, see Notify) and empty
+	// holds whether the SyncBuffer was empty at the last change.
+	notify chan struct{}
+	empty  bool
+}
+
+// NewSync creates a new SyncBuffer holding objects of the specified type.
+func NewSync[T any]() *SyncBuffer[T] {
+	return NewSyncWithSize[T](10, 5)
+}
+
+// NewSyncWithSize creates a new SyncBuffer with the specified row size and number of pre-allocated rows (see
+// NewWithSize).
+func NewSyncWithSize[T any](rowSize, rows int) *SyncBuffer[T] {
+	return &SyncBuffer[T]{buf: NewWithSize[T](rowSize, rows)}
+}
+
+// WithMaxBuffered bounds the number of unconsumed elements in the SyncBuffer to n. When the bound is reached
+// writes block until the consumer consumes elements. This gives backpressure between a fast producer and a slow
+// consumer. The SyncBuffer is returned. If n is <= 0 then a panic is raised.
+//
+// A WriteSlice of more elements than there is room for is split into several writes. Hence, the elements of
+// concurrent writes to a bounded SyncBuffer may be interleaved. Note that the consumer must never wait for more
+// than n buffered elements (e.g. using SyncBuffer.WaitUntil) as the producer can't write more.
+func (b *SyncBuffer[T]) WithMaxBuffered(n int) *SyncBuffer[T] {
+	if n <= 0 {
+		panic(argumentError{"illegal non-positive max buffered", n})
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.maxBuffered = n
+	return b
+}
+
+// Write writes an element to the SyncBuffer waking up any goroutine waiting for elements. If the SyncBuffer is
+// bounded (see SyncBuffer.WithMaxBuffered) then Write blocks until there is room for the element.
+func (b *SyncBuffer[T]) Write(element T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.awaitRoom()
+	if !b.checkOpen("Write") {
+		return
+	}
+	b.buf.Write(element)
+	b.signal()
+}
+
+// WriteSlice writes all elements in the provided slice to the SyncBuffer (see Buffer.WriteSlice) waking up any
+// goroutine waiting for elements. If the SyncBuffer is bounded (see SyncBuffer.WithMaxBuffered) then WriteSlice
+// blocks until all elements have been written.
+func (b *SyncBuffer[T]) WriteSlice(elements []T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.maxBuffered == 0 {
+		if b.checkOpen("WriteSlice") {
+			b.buf.WriteSlice(elements)
+			b.signal()
+		}
+		return
+	}
+	for len(elements) > 0 {
+		n := min(len(elements), b.awaitRoom())
+		if !b.checkOpen("WriteSlice") {
+			return
+		}
+		b.buf.WriteSlice(elements[:n])
+		elements = elements[n:]
+		b.signal()
+	}
+}
+
+// TryWrite writes an element to the SyncBuffer like SyncBuffer.Write but never blocks. If the SyncBuffer has
+// been closed then ClosedError is returned. If a bounded SyncBuffer is full (see SyncBuffer.WithMaxBuffered) then
+// BufferFullError is returned. Errors of the underlying Buffer (see Buffer.TryWrite) are returned as well.
+func (b *SyncBuffer[T]) TryWrite(element T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ClosedError
+	}
+	if b.maxBuffered > 0 && b.buf.Buffered() >= b.maxBuffered {
+		return b.buf.full()
+	}
+	if err := b.buf.TryWrite(element); err != nil {
+		return err
+	}
+	b.signal()
+	return nil
+}
+
+// Close signals the end of the elements written to the SyncBuffer. Goroutines waiting for the SyncBuffer are
+// woken up. The buffered elements may still be consumed. When all elements have been consumed SyncBuffer.Err
+// returns io.EOF. Writing to a closed SyncBuffer is a misuse with the error ClosedError (see MisusePolicy).
+// Closing a SyncBuffer more than once has no effect.
+func (b *SyncBuffer[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.signal()
+}
+
+// Err returns io.EOF if the SyncBuffer has been closed (see SyncBuffer.Close) and all elements have been consumed.
+// Otherwise, nil is returned.
+func (b *SyncBuffer[T]) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed && b.buf.Buffered() == 0 {
+		return io.EOF
+	}
+	return nil
+}
+
+// checkOpen returns true if the SyncBuffer may be written to by the method op. Writing to a closed SyncBuffer is
+// handled as a misuse (see MisusePolicy). The caller must hold the lock.
+func (b *SyncBuffer[T]) checkOpen(op string) bool {
+	if b.closed {
+		_ = b.buf.misused(op, ClosedError, false)
+		return false
+	}
+	return true
+}
+
+// awaitRoom waits until there is room for at least one more element in a bounded SyncBuffer (or the SyncBuffer
+// is closed) and returns the number of elements there is room for. The caller must hold the lock. The lock is
+// released while waiting.
+func (b *SyncBuffer[T]) awaitRoom() int {
+	if b.maxBuffered == 0 {
+		return math.MaxInt
+	}
+	for b.buf.Buffered() >= b.maxBuffered && !b.closed {
+		changed := b.waitChan()
+		b.mu.Unlock()
+		<-changed
+		b.mu.Lock()
+	}
+	return b.maxBuffered - b.buf.Buffered()
+}
+
+// Next returns the next element from the SyncBuffer (see Buffer.Next).
+func (b *SyncBuffer[T]) Next() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Next()
+}
+
+// Pop returns and consumes the next element from the SyncBuffer (see Buffer.Pop).
+func (b *SyncBuffer[T]) Pop() (element T, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if element, ok = b.buf.Pop(); ok {
+		b.signal()
+	}
+	return
+}
+
+// Consume will consume the next element in the SyncBuffer (see Buffer.Consume).
+func (b *SyncBuffer[T]) Consume() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Consume()
+	b.signal()
+}
+
+// ConsumeN consumes the next n elements in the SyncBuffer (see Buffer.ConsumeN).
+func (b *SyncBuffer[T]) ConsumeN(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.ConsumeN(n)
+	b.signal()
+}
+
+// State return a SyncBuffer state (see Buffer.State).
+func (b *SyncBuffer[T]) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.State()
+}
+
+// Rollback resets the SyncBuffer read state to the provided state (see Buffer.Rollback).
+func (b *SyncBuffer[T]) Rollback(state State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	err := b.buf.Rollback(state)
+	b.signal()
+	return err
+}
+
+// Commit will remove consumed elements from the SyncBuffer (see Buffer.Commit).
+func (b *SyncBuffer[T]) Commit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Commit()
+	b.signal()
+}
+
+// Buffered returns the number of unconsumed elements in the SyncBuffer.
+func (b *SyncBuffer[T]) Buffered() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Buffered()
+}
+
+// Do calls fn with the underlying Buffer while holding the lock of the SyncBuffer. Any goroutine waiting for the
+// SyncBuffer to change is woken up when fn returns. The Buffer must not be retained after fn returns.
+func (b *SyncBuffer[T]) Do(fn func(buf *Buffer[T])) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	defer b.signal()
+	fn(b.buf)
+}
+
+// Notify returns a channel receiving a value each time the SyncBuffer goes from empty to non-empty (e.g. by a
+// write to an empty SyncBuffer). If the SyncBuffer isn't empty when Notify is called then a value is sent
+// immediately. The channel holds at most one pending value. Hence, a slow receiver gets one value for several
+// transitions. The same channel is returned by all calls to Notify.
+func (b *SyncBuffer[T]) Notify() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.notify == nil {
+		b.notify = make(chan struct{}, 1)
+		b.empty = true
+		b.signal()
+	}
+	return b.notify
+}
+
+// signal wakes up all goroutines waiting for the SyncBuffer to change. The caller must hold the lock.
+func (b *SyncBuffer[T]) signal() {
+	if b.changed != nil {
+		close(b.changed)
+		b.changed = nil
+	}
+	if b.notify != nil {
+		empty := b.buf.Buffered() == 0
+		if b.empty && !empty {
+			select {
+			case b.notify <- struct{}{}:
+			default:
+			}
+		}
+		b.empty = empty
+	}
+}
+
+// waitChan returns a channel closed the next time the SyncBuffer changes. The caller must hold the lock.
+func (b *SyncBuffer[T]) waitChan() <-chan struct{} {
+	if b.changed == nil {
+		b.changed = make(chan struct{})
+	}
+	return b.changed
+}
+
+// WaitUntil blocks until pred returns true for the number of unconsumed elements in the SyncBuffer. The predicate
+// is evaluated (holding the lock) when WaitUntil is called and each time the SyncBuffer changes (e.g. by a write,
+// consume or rollback). If the context is done before the predicate returns true then the context error is
+// returned.
+//
+// If the SyncBuffer is closed (see SyncBuffer.Close) and the predicate returns false then io.EOF is returned (as
+// no more elements will be written).
+func (b *SyncBuffer[T]) WaitUntil(ctx context.Context, pred func(buffered int) bool) error {
+	for {
+		b.mu.Lock()
+		if pred(b.buf.Buffered()) {
+			b.mu.Unlock()
+			return nil
+		}
+		if b.closed {
+			b.mu.Unlock()
+			return io.EOF
+		}
+		changed := b.waitChan()
+		b.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// WaitFor blocks until at least n unconsumed elements are buffered in the SyncBuffer (see SyncBuffer.WaitUntil).
+// If the context is done before that then the context error is returned. If the SyncBuffer is closed (see
+// SyncBuffer.Close) with fewer than n buffered elements then io.EOF is returned.
+func (b *SyncBuffer[T]) WaitFor(ctx context.Context, n int) error {
+	return b.WaitUntil(ctx, func(buffered int) bool { return buffered >= n })
+}
+
+// Chan returns a channel streaming the elements of the SyncBuffer. A goroutine consumes (and commits) the
+// elements as they are delivered to the channel. Hence, the elements may be received using select together with
+// other channels. The channel is closed when the context is done or when the SyncBuffer has been closed (see
+// SyncBuffer.Close) and all elements have been delivered. An element not delivered when the context is done is
+// left unconsumed. The goroutine must be the only consumer of the SyncBuffer.
+func (b *SyncBuffer[T]) Chan(ctx context.Context) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for b.WaitUntil(ctx, func(buffered int) bool { return buffered > 0 }) == nil {
+			element, _ := b.Next()
+			select {
+			case ch <- element:
+			case <-ctx.Done():
+				return
+			}
+			b.mu.Lock()
+			b.buf.Consume()
+			b.buf.Commit()
+			b.signal()
+			b.mu.Unlock()
+		}
+	}()
+	return ch
+}
+
+// DrainToChanBatched consumes elements from the SyncBuffer and delivers them to the provided channel in batches.
+// A batch is delivered when it holds maxBatch elements or when maxDelay has passed since the first element was
+// added to the batch, whichever comes first. If maxDelay is <= 0 then a batch is delivered as soon as there are
+// no more buffered elements. Delivering batches instead of single elements reduces the per-element cost of
+// channel sends.
+//
+// The elements in a batch are consumed (and committed) when the batch has been delivered. DrainToChanBatched
+// keeps draining until the context is done and then returns the context error. If the SyncBuffer is closed (see
+// SyncBuffer.Close) then the last partial batch is delivered and io.EOF is returned. Elements in a batch not yet
+// delivered are left unconsumed. DrainToChanBatched must be the only consumer of the SyncBuffer. If maxBatch is
+// <= 0 then a panic is raised.
+func (b *SyncBuffer[T]) DrainToChanBatched(ctx context.Context, ch chan<- []T, maxBatch int,
+	maxDelay time.Duration) error {
+	if maxBatch <= 0 {
+		panic(argumentError{"illegal non-positive batch size", maxBatch})
+	}
+	for {
+		batch, err := b.collectBatch(ctx, maxBatch, maxDelay)
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- batch:
+		}
+		b.mu.Lock()
+		b.buf.consume(len(batch))
+		b.buf.Commit()
+		b.signal()
+		b.mu.Unlock()
+	}
+}
+
+// collectBatch collects (without consuming) a batch of elements to deliver (see SyncBuffer.DrainToChanBatched).
+func (b *SyncBuffer[T]) collectBatch(ctx context.Context, maxBatch int, maxDelay time.Duration) ([]T, error) {
+	var batch []T
+	var deadline <-chan time.Time
+	for {
+		b.mu.Lock()
+		from := b.buf.read.Move(len(batch))
+		to := from.Move(min(maxBatch-len(batch), b.buf.write.AbsolutePos()-from.AbsolutePos()))
+		b.buf.chunks(from, to, func(chunk []T) bool {
+			batch = append(batch, chunk...)
+			return true
+		})
+		if len(batch) == maxBatch || (len(batch) > 0 && maxDelay <= 0) {
+			b.mu.Unlock()
+			return batch, nil
+		}
+		if b.closed && len(batch) == b.buf.Buffered() {
+			// No more elements will be written. Hence, the partial batch is flushed.
+			b.mu.Unlock()
+			if len(batch) == 0 {
+				return nil, io.EOF
+			}
+			return batch, nil
+		}
+		changed := b.waitChan()
+		b.mu.Unlock()
+		if len(batch) > 0 && deadline == nil {
+			timer := time.NewTimer(maxDelay)
+			defer timer.Stop()
+			deadline = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return batch, nil
+		case <-changed:
+		}
+	}
+}
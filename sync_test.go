@@ -0,0 +1,357 @@
+package gobuffer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSyncBuffer(t *testing.T) {
+	buf := NewSyncWithSize[int](4, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			buf.Write(i)
+		}
+	}()
+	for i := 0; i < 1000; {
+		v, ok := buf.Next()
+		if !ok {
+			continue
+		}
+		if v != i {
+			t.Fatalf("unexpected next:\nexp=%d\ngot=%d", i, v)
+		}
+		buf.Consume()
+		if i%10 == 0 {
+			buf.Commit()
+		}
+		i++
+	}
+	<-done
+	if n := buf.Buffered(); n != 0 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 0, n)
+	}
+}
+
+func TestSyncBufferDrainToChanBatched(t *testing.T) {
+	buf := NewSyncWithSize[int](3, 1)
+	for i := 0; i < 10; i++ {
+		buf.Write(i)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan []int)
+	errCh := make(chan error)
+	go func() { errCh <- buf.DrainToChanBatched(ctx, ch, 4, 20*time.Millisecond) }()
+	for _, exp := range [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}, {8, 9}} {
+		if got := <-ch; !slices.Equal(got, exp) {
+			t.Errorf("unexpected batch:\nexp=%v\ngot=%v", exp, got)
+		}
+	}
+	// A partial batch is delivered when the delay has passed
+	start := time.Now()
+	buf.Write(10)
+	if got := <-ch; !slices.Equal(got, []int{10}) {
+		t.Errorf("unexpected batch:\nexp=%v\ngot=%v", []int{10}, got)
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("partial batch delivered too early: %v", elapsed)
+	}
+	buf.Write(11)
+	cancel()
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", context.Canceled, err)
+	}
+	if n := buf.Buffered(); n != 1 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 1, n)
+	}
+}
+
+func TestSyncBufferDrainToChanBatched_NoDelay(t *testing.T) {
+	buf := NewSync[int]()
+	buf.Write(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan []int, 1)
+	go func() { _ = buf.DrainToChanBatched(ctx, ch, 4, 0) }()
+	if got := <-ch; !slices.Equal(got, []int{1}) {
+		t.Errorf("unexpected batch:\nexp=%v\ngot=%v", []int{1}, got)
+	}
+}
+
+func TestSyncBufferDrainToChanBatched_MaxBuffered(t *testing.T) {
+	buf := NewSyncWithSize[int](2, 1).WithMaxBuffered(2)
+	go func() {
+		for i := 0; i < 10; i++ {
+			buf.Write(i)
+		}
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan []int)
+	go func() { _ = buf.DrainToChanBatched(ctx, ch, 2, 0) }()
+	// Delivering a batch makes room for the blocked producer
+	var got []int
+	for len(got) < 10 {
+		got = append(got, <-ch...)
+	}
+	if exp := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(got, exp) {
+		t.Errorf("unexpected elements:\nexp=%v\ngot=%v", exp, got)
+	}
+	// The delivered elements are committed
+	var rows int
+	buf.Do(func(buf *Buffer[int]) { rows = buf.RetentionReport().Rows })
+	if rows > 2 {
+		t.Errorf("unexpected rows:\nexp=<=%d\ngot=%d", 2, rows)
+	}
+}
+
+func TestSyncBufferDrainToChanBatched_Closed(t *testing.T) {
+	buf := NewSync[int]()
+	for i := 0; i < 5; i++ {
+		buf.Write(i)
+	}
+	buf.Close()
+	ch := make(chan []int, 3)
+	// The partial batch is flushed without waiting for the delay
+	err := buf.DrainToChanBatched(context.Background(), ch, 3, time.Hour)
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", io.EOF, err)
+	}
+	close(ch)
+	var got [][]int
+	for batch := range ch {
+		got = append(got, batch)
+	}
+	if exp := [][]int{{0, 1, 2}, {3, 4}}; !slices.EqualFunc(got, exp, slices.Equal) {
+		t.Errorf("unexpected batches:\nexp=%v\ngot=%v", exp, got)
+	}
+}
+
+func TestSyncBufferWaitUntil(t *testing.T) {
+	buf := NewSyncWithSize[int](4, 1)
+	go func() {
+		for i := 0; i < 10; i++ {
+			buf.Write(i)
+		}
+	}()
+	if err := buf.WaitUntil(context.Background(), func(buffered int) bool { return buffered >= 10 }); err != nil {
+		t.Fatalf("unexpected wait error: %v", err)
+	}
+	if n := buf.Buffered(); n != 10 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 10, n)
+	}
+	// Consuming also wakes up waiters
+	go func() {
+		for i := 0; i < 10; i++ {
+			buf.Consume()
+		}
+	}()
+	if err := buf.WaitUntil(context.Background(), func(buffered int) bool { return buffered == 0 }); err != nil {
+		t.Fatalf("unexpected wait error: %v", err)
+	}
+}
+
+func TestSyncBufferWaitUntil_Canceled(t *testing.T) {
+	buf := NewSync[int]()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := buf.WaitUntil(ctx, func(buffered int) bool { return buffered > 0 })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestSyncBufferDo(t *testing.T) {
+	buf := NewSyncWithSize[int](4, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := buf.WaitUntil(context.Background(), func(buffered int) bool { return buffered == 0 }); err != nil {
+			t.Errorf("unexpected wait error: %v", err)
+		}
+	}()
+	buf.WriteSlice([]int{1, 2, 3})
+	// Consume the pair atomically
+	buf.Do(func(b *Buffer[int]) {
+		if pair, ok := b.PeekN(2); ok && pair[0]+pair[1] == 3 {
+			b.ConsumeN(2)
+		}
+	})
+	if n := buf.Buffered(); n != 1 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 1, n)
+	}
+	buf.Do(func(b *Buffer[int]) { b.DiscardBuffered() })
+	<-done
+}
+
+func TestSyncBuffer_MultipleProducers(t *testing.T) {
+	buf := NewSyncWithSize[int](4, 1)
+	const producers, writes = 4, 250
+	for p := range producers {
+		go func() {
+			for i := range writes {
+				// Each write is a pair (producer, sequence number) written atomically
+				buf.WriteSlice([]int{p, i})
+			}
+		}()
+	}
+	next := make([]int, producers)
+	for i := 0; i < producers*writes; i++ {
+		if err := buf.WaitUntil(context.Background(), func(buffered int) bool { return buffered >= 2 }); err != nil {
+			t.Fatalf("unexpected wait error: %v", err)
+		}
+		var pair []int
+		buf.Do(func(b *Buffer[int]) {
+			pair, _ = b.PeekN(2)
+			pair = slices.Clone(pair)
+			b.ConsumeN(2)
+			b.Commit()
+		})
+		if p := pair[0]; pair[1] != next[p] {
+			t.Fatalf("unexpected sequence number of producer %d:\nexp=%d\ngot=%d", p, next[p], pair[1])
+		}
+		next[pair[0]]++
+	}
+}
+
+func TestSyncBufferWithMaxBuffered(t *testing.T) {
+	buf := NewSyncWithSize[int](4, 1).WithMaxBuffered(3)
+	peak := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf.WriteSlice([]int{0, 1, 2, 3, 4})
+		for i := 5; i < 100; i++ {
+			buf.Write(i)
+		}
+	}()
+	for i := 0; i < 100; {
+		if err := buf.WaitUntil(context.Background(), func(buffered int) bool {
+			peak = max(peak, buffered)
+			return buffered > 0
+		}); err != nil {
+			t.Fatalf("unexpected wait error: %v", err)
+		}
+		v, _ := buf.Pop()
+		if v != i {
+			t.Fatalf("unexpected element:\nexp=%d\ngot=%d", i, v)
+		}
+		i++
+	}
+	<-done
+	if peak > 3 {
+		t.Errorf("unexpected peak buffered:\nexp=<=%d\ngot=%d", 3, peak)
+	}
+}
+
+func TestSyncBufferClose(t *testing.T) {
+	buf := NewSyncWithSize[int](4, 1).WithMaxBuffered(2)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// The blocked write is woken up by the close
+		buf.WriteSlice([]int{1, 2, 3})
+	}()
+	if err := buf.WaitUntil(context.Background(), func(buffered int) bool { return buffered == 2 }); err != nil {
+		t.Fatalf("unexpected wait error: %v", err)
+	}
+	if err := buf.Err(); err != nil {
+		t.Errorf("unexpected error before close: %v", err)
+	}
+	buf.Close()
+	<-done
+	if err := buf.TryWrite(4); !errors.Is(err, ClosedError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", ClosedError, err)
+	}
+	var got []int
+	for v, ok := buf.Pop(); ok; v, ok = buf.Pop() {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("unexpected elements:\nexp=%v\ngot=%v", []int{1, 2}, got)
+	}
+	if err := buf.Err(); !errors.Is(err, io.EOF) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", io.EOF, err)
+	}
+	err := buf.WaitUntil(context.Background(), func(buffered int) bool { return buffered > 0 })
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("unexpected wait error:\nexp=%v\ngot=%v", io.EOF, err)
+	}
+}
+
+func TestSyncBufferChan(t *testing.T) {
+	buf := NewSyncWithSize[int](4, 1)
+	go func() {
+		for i := range 10 {
+			buf.Write(i)
+		}
+		buf.Close()
+	}()
+	var got []int
+	for v := range buf.Chan(context.Background()) {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("unexpected elements:\nexp=%v\ngot=%v", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, got)
+	}
+}
+
+func TestSyncBufferChan_Canceled(t *testing.T) {
+	buf := NewSyncWithSize[int](4, 1)
+	buf.WriteSlice([]int{1, 2, 3})
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := buf.Chan(ctx)
+	got := []int{<-ch}
+	cancel()
+	for v := range ch {
+		got = append(got, v)
+	}
+	// No element is lost. The undelivered elements are left unconsumed.
+	buf.Do(func(b *Buffer[int]) { got = append(got, b.ToSlice()...) })
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("unexpected elements:\nexp=%v\ngot=%v", []int{1, 2, 3}, got)
+	}
+}
+
+func TestSyncBufferNotify(t *testing.T) {
+	buf := NewSyncWithSize[int](4, 1)
+	notify := buf.Notify()
+	select {
+	case <-notify:
+		t.Fatalf("unexpected notification of empty buffer")
+	default:
+	}
+	buf.Write(1)
+	buf.Write(2)
+	<-notify
+	select {
+	case <-notify:
+		t.Fatalf("unexpected notification of non-empty buffer")
+	default:
+	}
+	buf.ConsumeN(2)
+	buf.Write(3)
+	<-notify
+}
+
+func TestSyncBufferWaitFor(t *testing.T) {
+	buf := NewSyncWithSize[byte](4, 1)
+	go func() {
+		for _, b := range []byte("header!") {
+			buf.Write(b)
+		}
+		buf.Close()
+	}()
+	if err := buf.WaitFor(context.Background(), 6); err != nil {
+		t.Fatalf("unexpected wait error: %v", err)
+	}
+	buf.ConsumeN(6)
+	if err := buf.WaitFor(context.Background(), 2); !errors.Is(err, io.EOF) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", io.EOF, err)
+	}
+}
@@ -0,0 +1,20 @@
+// Command tinygo exercises the core of the gobuffer package. It is built by TestTinyGo to verify that the package
+// builds for small targets like TinyGo and WASM.
+package main
+
+import "github.com/habak67/gobuffer"
+
+func main() {
+	buf := gobuffer.NewWithSize[rune](4, 1)
+	for _, r := range "let x = 42;" {
+		buf.Write(r)
+	}
+	state := buf.State()
+	buf.ConsumeN(4)
+	if err := buf.Rollback(state); err != nil {
+		panic(err)
+	}
+	if buf.String() != "let x = 42;" {
+		panic("unexpected content " + buf.String())
+	}
+}
@@ -0,0 +1,103 @@
+package gobuffer
+
+import "time"
+
+// TimedBuffer is a Buffer recording the time each element was written. The recorded times may be used to evict
+// elements based on their age rather than their count.
+//
+// The write times are held in a separate Buffer with the same geometry as the element Buffer. The two buffers
+// are always written, consumed, committed and rolled back together, and a State created by a TimedBuffer is
+// therefore valid for both.
+type TimedBuffer[T any] struct {
+	buf   *Buffer[T]
+	times *Buffer[time.Time]
+	now   func() time.Time
+}
+
+// NewTimed creates a new TimedBuffer holding objects of the specified type.
+func NewTimed[T any]() *TimedBuffer[T] {
+	return NewTimedWithSize[T](10, 5)
+}
+
+// NewTimedWithSize creates a new TimedBuffer with the specified row size and number of pre-allocated rows (see
+// NewWithSize).
+func NewTimedWithSize[T any](rowSize, rows int) *TimedBuffer[T] {
+	return &TimedBuffer[T]{
+		buf:   NewWithSize[T](rowSize, rows),
+		times: NewWithSize[time.Time](rowSize, rows),
+		now:   time.Now,
+	}
+}
+
+// Write writes an element to the TimedBuffer recording the current time as the write time of the element.
+func (b *TimedBuffer[T]) Write(element T) {
+	b.buf.Write(element)
+	b.times.Write(b.now())
+}
+
+// Next returns the next element from the TimedBuffer (see Buffer.Next).
+func (b *TimedBuffer[T]) Next() (T, bool) {
+	return b.buf.Next()
+}
+
+// NextWithTime returns the next element from the TimedBuffer together with the time the element was written.
+// If there are no unread elements in the buffer then false is returned.
+func (b *TimedBuffer[T]) NextWithTime() (element T, written time.Time, ok bool) {
+	if element, ok = b.buf.Next(); ok {
+		written, _ = b.times.Next()
+	}
+	return
+}
+
+// Consume will consume the next element in the TimedBuffer (see Buffer.Consume).
+func (b *TimedBuffer[T]) Consume() {
+	b.buf.Consume()
+	b.times.Consume()
+}
+
+// State return a TimedBuffer state (see Buffer.State).
+func (b *TimedBuffer[T]) State() State {
+	return b.buf.State()
+}
+
+// Rollback resets the TimedBuffer read state to the provided state (see Buffer.Rollback). The write times of the
+// elements are rolled back together with the elements.
+func (b *TimedBuffer[T]) Rollback(state State) error {
+	if err := b.buf.Rollback(state); err != nil {
+		return err
+	}
+	return b.times.Rollback(state)
+}
+
+// Commit will remove consumed elements (and their write times) from the TimedBuffer (see Buffer.Commit).
+func (b *TimedBuffer[T]) Commit() {
+	b.buf.Commit()
+	b.times.Commit()
+}
+
+// Buffered returns the number of unconsumed elements in the TimedBuffer.
+func (b *TimedBuffer[T]) Buffered() int {
+	return b.buf.Buffered()
+}
+
+// OldestAge returns the age of the next (and hence oldest) unconsumed element in the TimedBuffer. If there are
+// no unread elements in the buffer then false is returned.
+func (b *TimedBuffer[T]) OldestAge() (time.Duration, bool) {
+	written, ok := b.times.Next()
+	if !ok {
+		return 0, false
+	}
+	return b.now().Sub(written), true
+}
+
+// EvictOlderThan consumes all unconsumed elements written more than d ago. The number of evicted elements is
+// returned. As the elements are consumed (not removed) an eviction may be rolled back like any other consumption.
+// Call TimedBuffer.Commit to release the memory held by the evicted elements.
+func (b *TimedBuffer[T]) EvictOlderThan(d time.Duration) (evicted int) {
+	limit := b.now().Add(-d)
+	for written, ok := b.times.Next(); ok && written.Before(limit); written, ok = b.times.Next() {
+		b.Consume()
+		evicted++
+	}
+	return
+}
@@ -0,0 +1,47 @@
+package gobuffer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimedBuffer(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	buf := NewTimedWithSize[rune](2, 1)
+	buf.now = func() time.Time { return now }
+	for _, r := range "abcde" {
+		buf.Write(r)
+		now = now.Add(time.Second)
+	}
+	if age, ok := buf.OldestAge(); !ok || age != 5*time.Second {
+		t.Errorf("unexpected oldest age:\nexp=%v\ngot=%v (%v)", 5*time.Second, age, ok)
+	}
+	state := buf.State()
+	if n := buf.EvictOlderThan(3 * time.Second); n != 2 {
+		t.Errorf("unexpected evicted:\nexp=%d\ngot=%d", 2, n)
+	}
+	r, written, ok := buf.NextWithTime()
+	if !ok || r != 'c' || !written.Equal(now.Add(-3*time.Second)) {
+		t.Errorf("unexpected next:\nexp=%c (%v)\ngot=%c (%v)", 'c', now.Add(-3*time.Second), r, written)
+	}
+	if err := buf.Rollback(state); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	if age, _ := buf.OldestAge(); age != 5*time.Second {
+		t.Errorf("unexpected oldest age after rollback:\nexp=%v\ngot=%v", 5*time.Second, age)
+	}
+	buf.EvictOlderThan(2 * time.Second)
+	buf.Commit()
+	if n := buf.Buffered(); n != 2 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 2, n)
+	}
+	if age, _ := buf.OldestAge(); age != 2*time.Second {
+		t.Errorf("unexpected oldest age after commit:\nexp=%v\ngot=%v", 2*time.Second, age)
+	}
+	if n := buf.EvictOlderThan(0); n != 2 {
+		t.Errorf("unexpected evicted:\nexp=%d\ngot=%d", 2, n)
+	}
+	if _, ok := buf.OldestAge(); ok {
+		t.Errorf("unexpected oldest age for empty buffer")
+	}
+}
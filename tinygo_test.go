@@ -0,0 +1,65 @@
+package gobuffer
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestImports verifies that the package doesn't import the heavyweight packages avoided for small targets like
+// TinyGo and WASM (see argumentError).
+func TestImports(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("unexpected glob error: %v", err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("unexpected parse error: %v", err)
+		}
+		for _, spec := range f.Imports {
+			if path, _ := strconv.Unquote(spec.Path.Value); path == "fmt" || path == "reflect" {
+				t.Errorf("unexpected import of %s in %s", path, file)
+			}
+		}
+	}
+}
+
+// TestTinyGo builds testdata/tinygo for WASM using the go tool and (if installed) TinyGo.
+func TestTinyGo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping build in short mode")
+	}
+	tests := []struct {
+		name string
+		tool string
+		args []string
+		env  []string
+	}{
+		{"wasip1", "go", []string{"build"}, []string{"GOOS=wasip1", "GOARCH=wasm"}},
+		{"tinygo", "tinygo", []string{"build", "-target=wasip1"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tool, err := exec.LookPath(test.tool)
+			if err != nil {
+				t.Skipf("%s not installed", test.tool)
+			}
+			args := append(test.args, "-o", filepath.Join(t.TempDir(), "main.wasm"), "./testdata/tinygo")
+			cmd := exec.Command(tool, args...)
+			cmd.Env = append(os.Environ(), test.env...)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("unexpected build error: %v\n%s", err, out)
+			}
+		})
+	}
+}
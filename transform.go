@@ -0,0 +1,78 @@
+package gobuffer
+
+// Apply calls fn with a pointer to each unconsumed element in the Buffer allowing the elements to be modified in
+// place. No elements are consumed.
+func (b *Buffer[T]) Apply(fn func(*T)) {
+	b.unshare()
+	b.chunks(b.read, b.write, func(chunk []T) bool {
+		for i := range chunk {
+			fn(&chunk[i])
+		}
+		return true
+	})
+}
+
+// Replace replaces the first n unconsumed elements in the Buffer equal to old with new and returns the number
+// of replaced elements. If n < 0 then there is no limit on the number of replacements. No elements are consumed.
+func Replace[T comparable](b *Buffer[T], old, new T, n int) int {
+	b.unshare()
+	replaced := 0
+	b.chunks(b.read, b.write, func(chunk []T) bool {
+		for i := range chunk {
+			if replaced == n {
+				return false
+			}
+			if chunk[i] == old {
+				chunk[i] = new
+				replaced++
+			}
+		}
+		return true
+	})
+	return replaced
+}
+
+// Truncate keeps the first n unconsumed elements in the Buffer and discards the rest. That is, the write position
+// is moved back to the position after the n-th unconsumed element. If there are n or fewer unconsumed elements
+// then the Buffer is not changed. The discarded elements are passed to the commit finalizer (see
+// Buffer.WithCommitFinalizer).
+//
+// States created when the read position was beyond the kept elements are not valid anymore (see Buffer.Rollback).
+// Cursors having read beyond the kept elements are moved back to the new write position (see Buffer.NewCursor).
+// Truncating a frozen Buffer is a misuse (see Buffer.Freeze). If n is < 0 then a panic is raised.
+func (b *Buffer[T]) Truncate(n int) {
+	if n < 0 {
+		panic(argumentError{"illegal negative truncate length", n})
+	}
+	if n >= b.Buffered() {
+		return
+	}
+	if !b.checkWritable("Truncate") {
+		return
+	}
+	b.unshare()
+	to := b.read.Move(n)
+	// Clear discarded elements so they may be garbage collected
+	b.chunks(to, b.write, func(chunk []T) bool {
+		if b.commitFinalizer != nil {
+			for _, element := range chunk {
+				b.commitFinalizer(element)
+			}
+		}
+		clear(chunk)
+		return true
+	})
+	discarded := b.write.AbsolutePos() - to.AbsolutePos()
+	b.write = to
+	b.discardedBack(discarded)
+	b.clampCursors()
+}
+
+// DiscardBuffered consumes all unconsumed elements in the Buffer and returns the number of consumed elements. That
+// is, the read position is moved forward to the write position. The consumed elements are removed by the next
+// commit as usual.
+func (b *Buffer[T]) DiscardBuffered() int {
+	n := b.Buffered()
+	b.consume(n)
+	return n
+}
@@ -0,0 +1,122 @@
+package gobuffer
+
+import (
+	"errors"
+	"testing"
+	"unicode"
+)
+
+func TestBufferApply(t *testing.T) {
+	buf := NewWithSize[rune](3, 1)
+	for _, r := range "aBcDeFg" {
+		buf.Write(r)
+	}
+	buf.Consume()
+	buf.Apply(func(r *rune) { *r = unicode.ToUpper(*r) })
+	if got := readAll(buf); got != "BCDEFG" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "BCDEFG", got)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		exp      string
+		replaced int
+	}{
+		{"none", 0, "a-b-c-d", 0},
+		{"limited", 2, "a+b+c-d", 2},
+		{"all", -1, "a+b+c+d", 3},
+		{"limit above count", 5, "a+b+c+d", 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := NewWithSize[rune](3, 1)
+			for _, r := range "-a-b-c-d" {
+				buf.Write(r)
+			}
+			buf.Consume()
+			if n := Replace(buf, '-', '+', test.n); n != test.replaced {
+				t.Errorf("unexpected replaced:\nexp=%d\ngot=%d", test.replaced, n)
+			}
+			if got := readAll(buf); got != test.exp {
+				t.Errorf("unexpected content:\nexp=%s\ngot=%s", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestBufferTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		exp  string
+	}{
+		{"discard all", 0, "xy"},
+		{"keep some", 4, "abcdxy"},
+		{"keep all", 7, "abcdefgxy"},
+		{"beyond buffered", 10, "abcdefgxy"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := NewWithSize[rune](3, 1)
+			for _, r := range "-abcdefg" {
+				buf.Write(r)
+			}
+			buf.Consume()
+			buf.Truncate(test.n)
+			for _, r := range "xy" {
+				buf.Write(r)
+			}
+			if got := readAll(buf); got != test.exp {
+				t.Errorf("unexpected content:\nexp=%s\ngot=%s", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestBufferTruncate_InvalidatesState(t *testing.T) {
+	buf := NewWithSize[rune](3, 1)
+	for _, r := range "abcdef" {
+		buf.Write(r)
+	}
+	first := buf.State()
+	for i := 0; i < 4; i++ {
+		buf.Consume()
+	}
+	last := buf.State()
+	if err := buf.Rollback(first); err != nil {
+		t.Fatalf("unexpected rollback error: %v", err)
+	}
+	buf.Truncate(2)
+	if err := buf.Rollback(last); !errors.Is(err, IllegalStateError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", IllegalStateError, err)
+	}
+}
+
+func TestBufferTruncate_NegativePanic(t *testing.T) {
+	defer func() { _ = recover() }()
+
+	New[rune]().Truncate(-1)
+	t.Errorf("expected Truncate to panic")
+}
+
+func TestBufferDiscardBuffered(t *testing.T) {
+	buf := NewWithSize[rune](3, 1)
+	for _, r := range "abcdefg" {
+		buf.Write(r)
+	}
+	buf.Consume()
+	if n := buf.DiscardBuffered(); n != 6 {
+		t.Errorf("unexpected discarded:\nexp=%d\ngot=%d", 6, n)
+	}
+	if n := buf.DiscardBuffered(); n != 0 {
+		t.Errorf("unexpected discarded:\nexp=%d\ngot=%d", 0, n)
+	}
+	buf.Commit()
+	buf.Write('x')
+	if got := readAll(buf); got != "x" {
+		t.Errorf("unexpected content:\nexp=%s\ngot=%s", "x", got)
+	}
+}
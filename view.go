@@ -0,0 +1,181 @@
+package gobuffer
+
+// View is a readable view over the elements of a Buffer. A View supports the same one element lookahead
+// (View.Next and View.Consume) and the same rollback to a saved state (View.State and View.Rollback) as the
+// Buffer itself.
+//
+// A Buffer is itself a View (over all its elements). Hence, code accepting a View (or the smaller Reader and
+// Rollbacker interfaces) works with buffers, views and any alternative implementations.
+type View[T any] interface {
+	Reader[T]
+	Rollbacker
+}
+
+// filterView is a View over a Buffer only exposing the elements accepted by a predicate.
+type filterView[T any] struct {
+	buf  *Buffer[T]
+	pred func(T) bool
+}
+
+// FilterView creates a View over the provided Buffer only exposing elements for which the predicate returns true.
+// Elements not accepted by the predicate are transparently consumed in the underlying Buffer when the view is
+// read.
+//
+// As the view reads and consumes elements directly from the underlying Buffer, states created by the view are
+// ordinary Buffer states. That is, a state created by the view may be used to roll back the underlying Buffer and
+// vice versa.
+func FilterView[T any](buf *Buffer[T], pred func(T) bool) View[T] {
+	return &filterView[T]{
+		buf:  buf,
+		pred: pred,
+	}
+}
+
+func (v *filterView[T]) Next() (element T, ok bool) {
+	for {
+		element, ok = v.buf.Next()
+		if !ok || v.pred(element) {
+			return
+		}
+		v.buf.Consume()
+	}
+}
+
+func (v *filterView[T]) Consume() {
+	// Skip any rejected elements so we consume the element returned by Next
+	if _, ok := v.Next(); ok {
+		v.buf.Consume()
+	}
+}
+
+func (v *filterView[T]) State() State {
+	return v.buf.State()
+}
+
+func (v *filterView[T]) Rollback(state State) error {
+	return v.buf.Rollback(state)
+}
+
+// windowView is a View over the elements of a Buffer between two positions. The view has its own read position
+// and reading the view doesn't affect the read position of the Buffer.
+type windowView[T any] struct {
+	buf   *Buffer[T]
+	start position // start points to the first element in the window.
+	end   position // end points to the position after the last element in the window.
+	read  position // read points to the next element to read from the window.
+}
+
+// Window creates a View exposing the elements between the read positions of the two provided states. That is, the
+// elements consumed after the state from was created and before the state to was created.
+//
+// If any of the provided states is the "zero state" then a ZeroStateError is returned. If the window starts
+// before the first element still available in the Buffer or ends after the last written element, or if any of
+// the states was created by a Buffer with another row size (see Buffer.Rollback), then an IllegalStateError is
+// returned. If the state to was created before the state from then an IllegalWindowError is returned.
+//
+// The window is only valid until the next call to Buffer.Commit. After a commit the window may not be readable
+// anymore.
+func (b *Buffer[T]) Window(from, to State) (View[T], error) {
+	if !from.init || !to.init {
+		return nil, ZeroStateError
+	}
+	if from.read.rowSize != b.rowSize || to.read.rowSize != b.rowSize || from.read.Row < b.startRow ||
+		to.read.AbsolutePos() > b.write.AbsolutePos() {
+		return nil, IllegalStateError
+	}
+	if to.read.AbsolutePos() < from.read.AbsolutePos() {
+		return nil, IllegalWindowError
+	}
+	return &windowView[T]{
+		buf:   b,
+		start: from.read,
+		end:   to.read,
+		read:  from.read,
+	}, nil
+}
+
+func (v *windowView[T]) Next() (element T, ok bool) {
+	// The window is not readable if the read row has been removed by a commit or the element discarded
+	if v.read.AbsolutePos() >= v.end.AbsolutePos() || v.read.Row < v.buf.startRow ||
+		v.read.AbsolutePos() >= v.buf.write.AbsolutePos() {
+		return
+	}
+	ok = true
+	row, col := v.buf.bufferPos(v.read)
+	element = v.buf.buffers[row][col]
+	return
+}
+
+func (v *windowView[T]) Consume() {
+	if v.read.AbsolutePos() < v.end.AbsolutePos() {
+		v.read = v.read.Move(1)
+	}
+}
+
+func (v *windowView[T]) State() State {
+	return newState(v.read, v.end)
+}
+
+// Rollback resets the window read state to the provided state. If the provided state doesn't point into the
+// window then an IllegalStateError is returned.
+func (v *windowView[T]) Rollback(state State) error {
+	if !state.init {
+		return ZeroStateError
+	}
+	pos := state.read.AbsolutePos()
+	if state.read.rowSize != v.buf.rowSize || pos < v.start.AbsolutePos() || pos > v.end.AbsolutePos() {
+		return IllegalStateError
+	}
+	v.read = state.read
+	return nil
+}
+
+// strideView is a View over a Buffer only exposing every n-th element.
+type strideView[T any] struct {
+	buf   *Buffer[T]
+	n     int
+	phase int // phase holds the absolute position (modulo n) of the exposed elements.
+}
+
+// StrideView creates a View over the provided Buffer exposing every n-th element of the unconsumed elements. The
+// first exposed element is the next element in the Buffer when the view is created. The elements in between are
+// transparently consumed in the underlying Buffer when the view is read.
+//
+// The exposed elements are determined by their position in the Buffer. Hence, states created by the view are
+// ordinary Buffer states and a rollback will not affect which elements are exposed. If n is <= 0 then a panic
+// is raised.
+func StrideView[T any](buf *Buffer[T], n int) View[T] {
+	if n <= 0 {
+		panic(argumentError{"illegal non-positive stride", n})
+	}
+	return &strideView[T]{
+		buf:   buf,
+		n:     n,
+		phase: buf.read.AbsolutePos() % n,
+	}
+}
+
+func (v *strideView[T]) Next() (element T, ok bool) {
+	for {
+		element, ok = v.buf.Next()
+		if !ok || v.buf.read.AbsolutePos()%v.n == v.phase {
+			return
+		}
+		v.buf.Consume()
+	}
+}
+
+func (v *strideView[T]) Consume() {
+	// Skip any elements in between so we consume the element returned by Next
+	if _, ok := v.Next(); ok {
+		v.buf.Consume()
+	}
+}
+
+func (v *strideView[T]) State() State {
+	return v.buf.State()
+}
+
+func (v *strideView[T]) Rollback(state State) error {
+	return v.buf.Rollback(state)
+}
@@ -0,0 +1,177 @@
+package gobuffer
+
+import (
+	"errors"
+	"testing"
+	"unicode"
+)
+
+func TestFilterView(t *testing.T) {
+	buf := NewWithSize[rune](3, 1)
+	for _, r := range " a b  c " {
+		buf.Write(r)
+	}
+	view := FilterView(buf, func(r rune) bool { return !unicode.IsSpace(r) })
+	r, ok := view.Next()
+	if !ok || r != 'a' {
+		t.Errorf("unexpected next:\nexp=%c\ngot=%c (%v)", 'a', r, ok)
+	}
+	view.Consume()
+	state := view.State()
+	got := readView(view)
+	if got != "bc" {
+		t.Errorf("unexpected view content:\nexp=%s\ngot=%s", "bc", got)
+	}
+	if n := buf.Buffered(); n != 0 {
+		t.Errorf("expected skipped elements to be consumed in buffer (buffered %d)", n)
+	}
+	if err := view.Rollback(state); err != nil {
+		t.Errorf("unexpected rollback error: %v", err)
+	}
+	got = readView(view)
+	if got != "bc" {
+		t.Errorf("unexpected view content after rollback:\nexp=%s\ngot=%s", "bc", got)
+	}
+}
+
+func TestFilterView_ConsumeSkipsRejected(t *testing.T) {
+	buf := New[rune]()
+	for _, r := range "  ab" {
+		buf.Write(r)
+	}
+	view := FilterView(buf, func(r rune) bool { return r != ' ' })
+	// Consume without a preceding Next should still consume the first accepted element
+	view.Consume()
+	r, ok := buf.Next()
+	if !ok || r != 'b' {
+		t.Errorf("unexpected buffer next:\nexp=%c\ngot=%c (%v)", 'b', r, ok)
+	}
+}
+
+func readView(view View[rune]) string {
+	var s []rune
+	for r, ok := view.Next(); ok; r, ok = view.Next() {
+		s = append(s, r)
+		view.Consume()
+	}
+	return string(s)
+}
+
+func TestBufferWindow(t *testing.T) {
+	buf := NewWithSize[rune](3, 1)
+	for _, r := range "let x = 42;" {
+		buf.Write(r)
+	}
+	for i := 0; i < 4; i++ {
+		buf.Consume()
+	}
+	from := buf.State()
+	buf.Consume()
+	to := buf.State()
+	for i := 0; i < 3; i++ {
+		buf.Consume()
+	}
+	view, err := buf.Window(from, to)
+	if err != nil {
+		t.Fatalf("unexpected window error: %v", err)
+	}
+	state := view.State()
+	if got := readView(view); got != "x" {
+		t.Errorf("unexpected window content:\nexp=%s\ngot=%s", "x", got)
+	}
+	if err := view.Rollback(state); err != nil {
+		t.Errorf("unexpected window rollback error: %v", err)
+	}
+	if got := readView(view); got != "x" {
+		t.Errorf("unexpected window content after rollback:\nexp=%s\ngot=%s", "x", got)
+	}
+	// Reading the window must not disturb the buffer
+	r, ok := buf.Next()
+	if !ok || r != '4' {
+		t.Errorf("unexpected buffer next:\nexp=%c\ngot=%c (%v)", '4', r, ok)
+	}
+}
+
+func TestBufferWindow_Errors(t *testing.T) {
+	buf := NewWithSize[rune](2, 1)
+	for _, r := range "abcdef" {
+		buf.Write(r)
+	}
+	first := buf.State()
+	buf.Consume()
+	second := buf.State()
+	other := NewWithSize[rune](3, 1)
+	for _, r := range "abcdef" {
+		other.Write(r)
+	}
+	other.ConsumeN(4)
+	otherState := other.State()
+	tests := []struct {
+		name string
+		from State
+		to   State
+		err  error
+	}{
+		{"zero from", State{}, second, ZeroStateError},
+		{"zero to", first, State{}, ZeroStateError},
+		{"reversed", second, first, IllegalWindowError},
+		{"other row size", first, otherState, IllegalStateError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := buf.Window(test.from, test.to)
+			if !errors.Is(err, test.err) {
+				t.Errorf("unexpected error:\nexp=%v\ngot=%v", test.err, err)
+			}
+		})
+	}
+	// A window beyond the discarded elements
+	buf.ConsumeN(3)
+	late := buf.State()
+	_ = buf.Rollback(second)
+	buf.Truncate(1)
+	if _, err := buf.Window(second, late); !errors.Is(err, IllegalStateError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", IllegalStateError, err)
+	}
+	buf.WriteSlice([]rune("cdef"))
+	buf.ConsumeN(3)
+	buf.Commit()
+	if _, err := buf.Window(first, second); !errors.Is(err, IllegalStateError) {
+		t.Errorf("unexpected error:\nexp=%v\ngot=%v", IllegalStateError, err)
+	}
+}
+
+func TestStrideView(t *testing.T) {
+	buf := NewWithSize[rune](4, 1)
+	for _, r := range "xa12b34c5" {
+		buf.Write(r)
+	}
+	buf.Consume()
+	view := StrideView(buf, 3)
+	r, _ := view.Next()
+	if r != 'a' {
+		t.Errorf("unexpected next:\nexp=%c\ngot=%c", 'a', r)
+	}
+	view.Consume()
+	state := view.State()
+	if got := readView(view); got != "bc" {
+		t.Errorf("unexpected view content:\nexp=%s\ngot=%s", "bc", got)
+	}
+	if err := view.Rollback(state); err != nil {
+		t.Errorf("unexpected rollback error: %v", err)
+	}
+	if got := readView(view); got != "bc" {
+		t.Errorf("unexpected view content after rollback:\nexp=%s\ngot=%s", "bc", got)
+	}
+	// The element after 'c' is skipped when looking for the next exposed element
+	if n := buf.Buffered(); n != 0 {
+		t.Errorf("unexpected buffered:\nexp=%d\ngot=%d", 0, n)
+	}
+}
+
+func TestStrideView_NonPositivePanic(t *testing.T) {
+	defer func() { _ = recover() }()
+
+	_ = StrideView(New[rune](), 0)
+	t.Errorf("expected StrideView to panic")
+}
@@ -0,0 +1,36 @@
+package gobuffer
+
+import "slices"
+
+// watcher is a subscription to the elements written to a Buffer (see Buffer.Watch).
+type watcher[T any] struct {
+	fn func(T)
+}
+
+// Watch subscribes to the elements written to the Buffer. The function fn is called for each element written
+// (including elements pulled from a source) while the subscription is active. The subscription is independent of
+// the read position. That is, elements are passed to fn when written whether they are consumed or not. The
+// returned function cancels the subscription.
+//
+// The function fn is called synchronously by the writer and must not modify the Buffer.
+func (b *Buffer[T]) Watch(fn func(T)) (cancel func()) {
+	w := &watcher[T]{fn: fn}
+	b.watchers = append(b.watchers, w)
+	return func() {
+		// Don't modify the watchers in place as they may be iterated when cancelled
+		b.watchers = slices.DeleteFunc(slices.Clone(b.watchers), func(other *watcher[T]) bool { return other == w })
+	}
+}
+
+// notifyWatchers passes the elements written between the provided position and the write position to the
+// watchers.
+func (b *Buffer[T]) notifyWatchers(from position) {
+	for _, w := range b.watchers {
+		b.chunks(from, b.write, func(chunk []T) bool {
+			for _, element := range chunk {
+				w.fn(element)
+			}
+			return true
+		})
+	}
+}
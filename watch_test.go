@@ -0,0 +1,44 @@
+package gobuffer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBufferWatch(t *testing.T) {
+	buf := NewFromSourceWithSize[rune](NewRuneSource(strings.NewReader("cd")), 3, 1)
+	var first, second []rune
+	cancelFirst := buf.Watch(func(r rune) { first = append(first, r) })
+	buf.Watch(func(r rune) { second = append(second, r) })
+	buf.Write('a')
+	buf.Consume()
+	other := New[rune]()
+	other.Write('b')
+	buf.MergeFrom(other)
+	_ = readAll(buf)
+	cancelFirst()
+	buf.Write('e')
+	if string(first) != "abcd" {
+		t.Errorf("unexpected first watched:\nexp=%s\ngot=%s", "abcd", string(first))
+	}
+	if string(second) != "abcde" {
+		t.Errorf("unexpected second watched:\nexp=%s\ngot=%s", "abcde", string(second))
+	}
+}
+
+func TestBufferWatch_CancelWhileNotified(t *testing.T) {
+	buf := New[int]()
+	var cancel func()
+	var got []int
+	cancel = buf.Watch(func(i int) {
+		got = append(got, i)
+		cancel()
+	})
+	buf.Watch(func(i int) { got = append(got, i*10) })
+	buf.Write(1)
+	buf.Write(2)
+	if exp := []int{1, 10, 20}; !slices.Equal(got, exp) {
+		t.Errorf("unexpected watched:\nexp=%v\ngot=%v", exp, got)
+	}
+}